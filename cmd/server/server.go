@@ -9,6 +9,7 @@ import (
 	"github.com/omidnikrah/duckparty-backend/internal/client"
 	"github.com/omidnikrah/duckparty-backend/internal/config"
 	"github.com/omidnikrah/duckparty-backend/internal/database"
+	"github.com/omidnikrah/duckparty-backend/internal/middleware"
 	"github.com/omidnikrah/duckparty-backend/internal/routes"
 	"github.com/omidnikrah/duckparty-backend/internal/storage"
 	ws "github.com/omidnikrah/duckparty-backend/internal/websocket"
@@ -31,12 +32,28 @@ func Setup() {
 
 	resendClient := client.NewResendClient(config)
 
-	r2Storage, err := storage.NewR2Storage(config)
+	fileStorage, err := storage.New(config)
 	if err != nil {
-		panic("failed to initialize R2 storage: " + err.Error())
+		panic("failed to initialize storage: " + err.Error())
 	}
 
-	cronScheduler, err := client.NewCron(context.Background(), db, slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broadcaster := ws.NewSocketBroadcaster(rdb, ws.BroadcasterOptions{
+		Authenticate: func(token string) (uint, error) {
+			authUser, err := middleware.ParseToken(config, token)
+			if err != nil {
+				return 0, err
+			}
+			return authUser.UserID, nil
+		},
+		MaxConnectionsPerUser: config.WSMaxConnsPerUser,
+		HistorySize:           config.WSHistorySize,
+	})
+	broadcaster.Start(ctx)
+
+	cronScheduler, err := client.NewCron(ctx, db, fileStorage, broadcaster, config.Reactions, slog.Default())
 	if err != nil {
 		panic("failed to initialize cron: " + err.Error())
 	}
@@ -46,16 +63,19 @@ func Setup() {
 		}
 	}()
 
-	router := gin.Default()
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
 	corsConfig.AddAllowHeaders("Authorization")
 	router.Use(cors.New(corsConfig))
 
-	broadcaster := ws.NewSocketBroadcaster()
+	if localStorage, ok := fileStorage.(*storage.LocalStorage); ok {
+		router.Static(storage.LocalRoutePath, localStorage.Root())
+	}
 
-	routes.SetupRoutes(router, db, rdb, resendClient, r2Storage, config, broadcaster)
+	routes.SetupRoutes(router, db, rdb, resendClient, fileStorage, config, broadcaster)
 
 	router.Run(":" + config.AppPort)
 }
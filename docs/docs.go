@@ -16,6 +16,229 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/cosmetics": {
+            "get": {
+                "description": "Returns every cosmetic, including retired and upcoming ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get all cosmetics",
+                "responses": {
+                    "200": {
+                        "description": "All cosmetics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Cosmetic"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Adds a skin or accessory to the catalog. It can be used in appearances as soon as its availability window opens.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Add a cosmetic",
+                "parameters": [
+                    {
+                        "description": "Cosmetic",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateCosmeticRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created cosmetic",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cosmetic"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cosmetic already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ]
+            }
+        },
+        "/admin/cosmetics/{cosmeticId}": {
+            "delete": {
+                "description": "Closes the availability window of a cosmetic now. Ducks already wearing it keep it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Retire a cosmetic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cosmetic ID",
+                        "name": "cosmeticId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Retired cosmetic",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cosmetic"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Cosmetic not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ]
+            }
+        },
+        "/admin/websocket/stats": {
+            "get": {
+                "description": "Returns the number of open WebSocket connections and slow-consumer evictions on the instance serving the request. Each replica keeps its own stats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get WebSocket stats",
+                "responses": {
+                    "200": {
+                        "description": "WebSocket stats",
+                        "schema": {
+                            "$ref": "#/definitions/WebSocketStats"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ]
+            }
+        },
         "/auth": {
             "post": {
                 "description": "Sends a one-time password (OTP) to the user's email address for authentication",
@@ -148,14 +371,44 @@ const docTemplate = `{
                 }
             }
         },
+        "/cosmetics": {
+            "get": {
+                "description": "Returns the skins and accessories that can currently be used in a duck's appearance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cosmetics"
+                ],
+                "summary": "Get available cosmetics",
+                "responses": {
+                    "200": {
+                        "description": "Available cosmetics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Cosmetic"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/duck": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new duck with image, name, email, and appearance data",
+                "description": "Creates a new duck with name and appearance data. Without an image, the duck image is rendered on the server from its appearance. Uploaded images, where allowed, must be a PNG, JPEG, GIF or WebP of at most 5 MiB and 2048x2048px; every image is stored as PNG alongside medium and thumbnail variants.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -169,10 +422,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "Duck image file",
+                        "description": "Duck image file; rejected when uploads are disabled",
                         "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "in": "formData"
                     },
                     {
                         "type": "string",
@@ -187,6 +439,18 @@ const docTemplate = `{
                         "name": "appearance",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "X position on the party canvas; omit x and y to auto-place",
+                        "name": "x",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Y position on the party canvas; omit x and y to auto-place",
+                        "name": "y",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -205,48 +469,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Error message",
+                    "403": {
+                        "description": "Appearance uses a cosmetic the owner has not unlocked",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/duck/{duckId}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Deletes a duck owned by the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ducks"
-                ],
-                "summary": "Remove a duck",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Duck ID",
-                        "name": "duckId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success message",
+                    },
+                    "409": {
+                        "description": "No free space left on the canvas",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -254,8 +487,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid duck ID",
+                    "413": {
+                        "description": "Image too large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -263,7 +496,544 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Duck image cannot be rendered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/duck/{duckId}": {
+            "put": {
+                "description": "Changes the name and appearance of a duck owned by the authenticated user, and optionally replaces its image. Without a new image, the image is re-rendered when the appearance changes, and kept as it is when the new appearance cannot be rendered. Cosmetics the duck already wears can be kept after they are retired. The previous values are kept in the duck's edit history.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Edit a duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Duck name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Duck appearance JSON",
+                        "name": "appearance",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Replacement duck image",
+                        "name": "image",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated duck",
+                        "schema": {
+                            "$ref": "#/definitions/DuckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Appearance uses a cosmetic the owner has not unlocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Image too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Moves a duck owned by the authenticated user to their trash. It can be restored for a limited time before it is deleted for good.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Remove a duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/duck/{duckId}/edits": {
+            "get": {
+                "description": "Returns every edit made to a duck, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Get a duck's edit history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Edit history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DuckEdit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/duck/{duckId}/position": {
+            "patch": {
+                "description": "Moves a duck owned by the authenticated user to a new position on the party canvas. The position must be on the canvas and keep the minimum spacing from other ducks. Moves are rate limited per user and per duck, and the limit is enforced separately by each server instance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Move a duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New position",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/duck_dto.MoveDuckDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moved duck",
+                        "schema": {
+                            "$ref": "#/definitions/DuckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID or position",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many moves for this duck",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/duck/{duckId}/reaction": {
+            "delete": {
+                "description": "Takes back the authenticated user's reaction to a duck. Succeeds even if the user had not reacted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Remove a reaction from a duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duck with updated reaction counts",
+                        "schema": {
+                            "$ref": "#/definitions/DuckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/duck/{duckId}/reaction/{reaction}": {
+            "put": {
+                "description": "Add a reaction to a duck. Reacting again with a different type replaces the previous reaction. The available types are listed by GET /reactions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "React to a duck",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reaction type, e.g. quack",
+                        "name": "reaction",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reaction created",
+                        "schema": {
+                            "$ref": "#/definitions/DuckReactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID or unknown reaction type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Duck already reacted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/duck/{duckId}/reactions": {
+            "get": {
+                "description": "Returns a page of the users who reacted to a duck, newest reaction first. Use next_cursor to fetch the following page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Get reactions to a duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reactions of this type",
+                        "name": "reaction",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of reactions",
+                        "schema": {
+                            "$ref": "#/definitions/DuckReactionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
                         "description": "Duck not found",
                         "schema": {
                             "type": "object",
@@ -284,14 +1054,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/duck/{duckId}/reaction/{reaction}": {
-            "put": {
+        "/duck/{duckId}/restore": {
+            "post": {
+                "description": "Brings a removed duck back from the authenticated user's trash. Ducks can be restored for a limited time after removal. If its old spot has been taken, the duck is placed somewhere free.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Restore a removed duck",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duck ID",
+                        "name": "duckId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored duck",
+                        "schema": {
+                            "$ref": "#/definitions/DuckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duck ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Duck not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "No free space left on the canvas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Duck can no longer be restored",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Add a like or dislike reaction to a duck",
+                ]
+            }
+        },
+        "/ducks": {
+            "get": {
+                "description": "Returns a page of ducks ordered by creation date (newest first). Use next_cursor to fetch the following page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -301,32 +1148,194 @@ const docTemplate = `{
                 "tags": [
                     "ducks"
                 ],
-                "summary": "React to a duck",
+                "summary": "Get list of ducks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Duck ID",
-                        "name": "duckId",
-                        "in": "path",
+                        "description": "Opaque cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by skin",
+                        "name": "skin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by accessory",
+                        "name": "accessory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by owner ID",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ducks created after this time (RFC3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ducks created before this time (RFC3339)",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of ducks",
+                        "schema": {
+                            "$ref": "#/definitions/DuckListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ducks/viewport": {
+            "get": {
+                "description": "Returns the ducks inside the given region of the party canvas. When the region holds more ducks than the limit, items is empty, truncated is true and density summarizes how many ducks are in each cell of a 16x16 grid over the region.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Get ducks inside a viewport",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Left edge of the viewport",
+                        "name": "minX",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Top edge of the viewport",
+                        "name": "minY",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Right edge of the viewport",
+                        "name": "maxX",
+                        "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "number",
+                        "description": "Bottom edge of the viewport",
+                        "name": "maxY",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most ducks to list before summarizing (default 200, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ducks in the viewport",
+                        "schema": {
+                            "$ref": "#/definitions/DuckViewportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard": {
+            "get": {
+                "description": "Returns the top ducks of the current day, week (starting Monday) or month in UTC, ranked by the reactions they received in that period, or the all-time top ducks. Day, week and month rankings are refreshed at most every 30 seconds. Positive reactions rank a duck higher; negative ones break ties. Without period, the response keeps its original format: a plain array of the all-time top ducks (DuckResponse), sorted by rank. With period, including period=all, it is a LeaderboardResponse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get ducks leaderboard",
+                "parameters": [
                     {
                         "enum": [
-                            "like",
-                            "dislike"
+                            "day",
+                            "week",
+                            "month",
+                            "all"
                         ],
                         "type": "string",
-                        "description": "Reaction type (like or dislike)",
-                        "name": "reaction",
-                        "in": "path",
-                        "required": true
+                        "description": "Leaderboard period; omit for the original all-time array",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ducks (default and max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Reaction created",
+                        "description": "Leaderboard, or an array of DuckResponse without period",
                         "schema": {
-                            "$ref": "#/definitions/DuckReactionResponse"
+                            "$ref": "#/definitions/LeaderboardResponse"
                         }
                     },
                     "400": {
@@ -338,8 +1347,66 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Duck not found",
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard/history": {
+            "get": {
+                "description": "Returns the top ducks of a finished day, week or month, as saved when the period ended. Without at, the last finished period is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Get past leaderboard winners",
+                "parameters": [
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Leaderboard period",
+                        "name": "period",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Any time within the period (RFC3339)",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of ducks (default and max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Leaderboard snapshot",
+                        "schema": {
+                            "$ref": "#/definitions/LeaderboardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error message",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -347,8 +1414,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Duck already reacted",
+                    "404": {
+                        "description": "No snapshot for this period",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -368,9 +1435,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ducks": {
+        "/reactions": {
             "get": {
-                "description": "Returns a list of all ducks ordered by creation date",
+                "description": "Returns the reactions users can leave on ducks, in display order",
                 "consumes": [
                     "application/json"
                 ],
@@ -380,32 +1447,23 @@ const docTemplate = `{
                 "tags": [
                     "ducks"
                 ],
-                "summary": "Get list of ducks",
+                "summary": "Get reaction types",
                 "responses": {
                     "200": {
-                        "description": "List of ducks",
+                        "description": "Reaction types",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/DuckResponse"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Error message",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                                "$ref": "#/definitions/ReactionTypeResponse"
                             }
                         }
                     }
                 }
             }
         },
-        "/leaderboard": {
+        "/user": {
             "get": {
-                "description": "Returns the top 100 ducks sorted by rank (highest to lowest)",
+                "description": "Returns the information of the currently authenticated user, including the cosmetics they can use",
                 "consumes": [
                     "application/json"
                 ],
@@ -413,20 +1471,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ducks"
+                    "user"
                 ],
-                "summary": "Get ducks leaderboard",
+                "summary": "Get current user information",
                 "responses": {
                     "200": {
-                        "description": "List of top 100 ducks by rank",
+                        "description": "User information",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/DuckResponse"
-                            }
+                            "$ref": "#/definitions/UserInfoResponse"
                         }
                     },
-                    "500": {
+                    "400": {
                         "description": "Error message",
                         "schema": {
                             "type": "object",
@@ -435,17 +1490,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/user": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Returns the information of the currently authenticated user",
+                ]
+            }
+        },
+        "/user/change-name": {
+            "put": {
+                "description": "Updates the display name of the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -455,10 +1510,21 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Get current user information",
+                "summary": "Update user display name",
+                "parameters": [
+                    {
+                        "description": "New display name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateNameRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Updated user",
                         "schema": {
                             "$ref": "#/definitions/UserInfoResponse"
                         }
@@ -472,17 +1538,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/user/change-name": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Updates the display name of the authenticated user",
+                ]
+            }
+        },
+        "/user/reactions": {
+            "get": {
+                "description": "Returns a page of the ducks the authenticated user reacted to, newest reaction first. Use next_cursor to fetch the following page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -490,25 +1556,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "ducks"
                 ],
-                "summary": "Update user display name",
+                "summary": "Get my reactions",
                 "parameters": [
                     {
-                        "description": "New display name",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UpdateNameRequest"
-                        }
+                        "type": "string",
+                        "description": "Opaque cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reactions of this type",
+                        "name": "reaction",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated user",
+                        "description": "Page of reactions",
                         "schema": {
-                            "$ref": "#/definitions/UserInfoResponse"
+                            "$ref": "#/definitions/DuckReactionListResponse"
                         }
                     },
                     "400": {
@@ -519,17 +1594,26 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
-                }
-            }
-        },
-        "/user/set-email": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/user/set-email": {
+            "post": {
                 "description": "Sends an OTP to the new email address for verification. Use /user/verify-email to verify and set the email.",
                 "consumes": [
                     "application/json"
@@ -571,16 +1655,56 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/user/verify-set-email": {
-            "post": {
+        "/user/trash": {
+            "get": {
+                "description": "Returns the authenticated user's removed ducks that can still be restored, most recently removed first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ducks"
+                ],
+                "summary": "Get removed ducks",
+                "responses": {
+                    "200": {
+                        "description": "Removed ducks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TrashedDuckResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/user/verify-set-email": {
+            "post": {
                 "description": "Verifies the OTP code and sets the email address for the authenticated user. Returns updated user and new token.",
                 "consumes": [
                     "application/json"
@@ -619,12 +1743,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/user/{userId}/ducks": {
             "get": {
-                "description": "Returns a list of all ducks owned by the specified user, ordered by creation date",
+                "description": "Returns a page of ducks owned by the specified user, ordered by creation date (newest first)",
                 "consumes": [
                     "application/json"
                 ],
@@ -642,16 +1771,49 @@ const docTemplate = `{
                         "name": "userId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by skin",
+                        "name": "skin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by accessory",
+                        "name": "accessory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ducks created after this time (RFC3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only ducks created before this time (RFC3339)",
+                        "name": "created_before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of user's ducks",
+                        "description": "Page of user's ducks",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/DuckResponse"
-                            }
+                            "$ref": "#/definitions/DuckListResponse"
                         }
                     },
                     "400": {
@@ -677,7 +1839,7 @@ const docTemplate = `{
         },
         "/ws": {
             "get": {
-                "description": "Establishes a WebSocket connection to receive real-time notifications when new ducks are added",
+                "description": "Establishes a WebSocket connection to receive real-time notifications: new_duck_created, duck_reaction_changed, duck_removed, duck_restored, duck_updated, duck_renamed, duck_moved and leaderboard_updated.\nConnections start subscribed to the \"ducks\" topic. Send {\"action\":\"subscribe\",\"topics\":[...]} or {\"action\":\"unsubscribe\",\"topics\":[...]} to change subscriptions; available topics are \"ducks\", \"leaderboard\", \"duck:{id}\" and \"user:{id}\".\nAuthenticate with a JWT either through the token query parameter or by sending {\"action\":\"auth\",\"token\":\"...\"} as a message. Authenticated connections are subscribed to their own \"user:{id}\" topic, which carries duck_reaction_received notifications; other users' topics cannot be subscribed to.\nEvery broadcast notification carries an increasing \"id\". After reconnecting, send {\"action\":\"resume\",\"last_id\":N} to receive the missed notifications for the current subscriptions followed by a \"resumed\" message, or a \"resync_required\" message when they are no longer retained and the client has to reload.",
                 "consumes": [
                     "application/json"
                 ],
@@ -688,6 +1850,14 @@ const docTemplate = `{
                     "websocket"
                 ],
                 "summary": "WebSocket connection for real-time duck notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT used to authenticate the connection",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "101": {
                         "description": "Switching Protocols"
@@ -700,6 +1870,33 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many connections for this user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
@@ -721,56 +1918,208 @@ const docTemplate = `{
                 "AccessoryVespaHelmet"
             ]
         },
-        "AuthenticateRequest": {
+        "AuthenticateRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "otp": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "AuthenticateResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/UserResponse"
+                }
+            }
+        },
+        "CosmeticRarity": {
+            "type": "string",
+            "enum": [
+                "common",
+                "rare",
+                "epic",
+                "legendary"
+            ],
+            "x-enum-varnames": [
+                "RarityCommon",
+                "RarityRare",
+                "RarityEpic",
+                "RarityLegendary"
+            ]
+        },
+        "CosmeticSlot": {
+            "type": "string",
+            "enum": [
+                "skin",
+                "head",
+                "back"
+            ],
+            "x-enum-varnames": [
+                "SlotSkin",
+                "SlotHead",
+                "SlotBack"
+            ]
+        },
+        "CosmeticUnlockRule": {
+            "type": "string",
+            "enum": [
+                "free",
+                "first_duck",
+                "top_leaderboard",
+                "likes_received"
+            ],
+            "x-enum-varnames": [
+                "UnlockFree",
+                "UnlockFirstDuck",
+                "UnlockTopLeaderboard",
+                "UnlockLikesReceived"
+            ]
+        },
+        "CreateAnonymousUserRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateCosmeticRequest": {
             "type": "object",
             "required": [
-                "email"
+                "display_name",
+                "id",
+                "slot"
             ],
             "properties": {
-                "email": {
+                "asset_url": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "https://example.com/cosmetics/party_hat.png"
                 },
-                "otp": {
+                "available_from": {
                     "type": "string",
-                    "example": "123456"
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "available_until": {
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00Z"
+                },
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Party Hat"
+                },
+                "id": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "party_hat"
+                },
+                "rarity": {
+                    "type": "string",
+                    "enum": [
+                        "common",
+                        "rare",
+                        "epic",
+                        "legendary"
+                    ],
+                    "example": "rare"
+                },
+                "slot": {
+                    "type": "string",
+                    "enum": [
+                        "skin",
+                        "head",
+                        "back"
+                    ],
+                    "example": "head"
+                },
+                "unlock_rule": {
+                    "description": "UnlockRule defaults to free. top_leaderboard and likes_received need\nan unlock_threshold.",
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "first_duck",
+                        "top_leaderboard",
+                        "likes_received"
+                    ],
+                    "example": "likes_received"
+                },
+                "unlock_threshold": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50
                 }
             }
         },
-        "AuthenticateResponse": {
+        "DuckAppearance": {
             "type": "object",
+            "required": [
+                "skin"
+            ],
             "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                "accessories": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/AccessoryType"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/UserResponse"
+                "skin": {
+                    "$ref": "#/definitions/SkinType"
                 }
             }
         },
-        "CreateAnonymousUserRequest": {
+        "DuckListResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
-                    "type": "string"
+                "has_more": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DuckResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoxfQ"
                 }
             }
         },
-        "DuckAppearance": {
+        "DuckReactionListResponse": {
             "type": "object",
             "properties": {
-                "accessories": {
+                "has_more": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/AccessoryType"
+                        "$ref": "#/definitions/DuckReactionResponse"
                     }
                 },
-                "skin": {
-                    "$ref": "#/definitions/SkinType"
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoxfQ"
                 }
             }
         },
@@ -789,16 +2138,12 @@ const docTemplate = `{
                     "example": 1
                 },
                 "reaction": {
-                    "enum": [
-                        "like",
-                        "dislike"
-                    ],
                     "allOf": [
                         {
                             "$ref": "#/definitions/ReactionType"
                         }
                     ],
-                    "example": "like"
+                    "example": "quack"
                 },
                 "user": {
                     "$ref": "#/definitions/DuckUserResponse"
@@ -829,7 +2174,15 @@ const docTemplate = `{
                 },
                 "image": {
                     "type": "string",
-                    "example": "https://example.com/image.jpg"
+                    "example": "https://example.com/image.png"
+                },
+                "image_medium": {
+                    "type": "string",
+                    "example": "https://example.com/image_medium.png"
+                },
+                "image_thumbnail": {
+                    "type": "string",
+                    "example": "https://example.com/image_thumb.png"
                 },
                 "likes_count": {
                     "type": "integer",
@@ -850,6 +2203,13 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "reaction_counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
@@ -875,10 +2235,6 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John Doe"
                 },
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
-                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -889,6 +2245,107 @@ const docTemplate = `{
                 }
             }
         },
+        "DuckViewportResponse": {
+            "type": "object",
+            "properties": {
+                "density": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/duck_dto.DuckDensityCell"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DuckResponse"
+                    }
+                },
+                "total": {
+                    "description": "Total is how many ducks are inside the viewport.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "truncated": {
+                    "description": "Truncated is true when there were too many ducks to list; Density\nthen summarizes where they are instead.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "InventoryItemResponse": {
+            "type": "object",
+            "properties": {
+                "cosmetic": {
+                    "$ref": "#/definitions/model.Cosmetic"
+                },
+                "unlocked_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "duck": {
+                    "description": "Duck is left out for past winners that have since been deleted.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/DuckResponse"
+                        }
+                    ]
+                },
+                "duck_image": {
+                    "type": "string",
+                    "example": "https://example.com/image.png"
+                },
+                "duck_name": {
+                    "type": "string",
+                    "example": "Ducky"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reaction_counts": {
+                    "description": "ReactionCounts are the reactions the duck received during the period.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "LeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-01-08T00:00:00Z"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LeaderboardEntry"
+                    }
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "all"
+                    ],
+                    "example": "week"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
         "ReactionType": {
             "type": "string",
             "enum": [
@@ -900,6 +2357,24 @@ const docTemplate = `{
                 "ReactionDislike"
             ]
         },
+        "ReactionTypeResponse": {
+            "type": "object",
+            "properties": {
+                "negative": {
+                    "description": "Negative reactions count against a duck in the leaderboard.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ReactionType"
+                        }
+                    ],
+                    "example": "quack"
+                }
+            }
+        },
         "SetEmailRequest": {
             "type": "object",
             "required": [
@@ -924,6 +2399,18 @@ const docTemplate = `{
                 "SkinSuperman"
             ]
         },
+        "TrashedDuckResponse": {
+            "type": "object",
+            "properties": {
+                "duck": {
+                    "$ref": "#/definitions/DuckResponse"
+                },
+                "restorable_until": {
+                    "type": "string",
+                    "example": "2024-01-08T00:00:00Z"
+                }
+            }
+        },
         "UpdateNameRequest": {
             "type": "object",
             "required": [
@@ -938,6 +2425,12 @@ const docTemplate = `{
         "UserInfoResponse": {
             "type": "object",
             "properties": {
+                "inventory": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/InventoryItemResponse"
+                    }
+                },
                 "user": {
                     "$ref": "#/definitions/UserResponse"
                 }
@@ -967,9 +2460,141 @@ const docTemplate = `{
                     "example": "user@example.com"
                 }
             }
+        },
+        "WebSocketStats": {
+            "type": "object",
+            "properties": {
+                "connections": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "evictions": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "duck_dto.DuckDensityCell": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "max_x": {
+                    "type": "number",
+                    "example": 500
+                },
+                "max_y": {
+                    "type": "number",
+                    "example": 500
+                },
+                "min_x": {
+                    "type": "number",
+                    "example": 0
+                },
+                "min_y": {
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "duck_dto.MoveDuckDTO": {
+            "type": "object",
+            "required": [
+                "x",
+                "y"
+            ],
+            "properties": {
+                "x": {
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Cosmetic": {
+            "type": "object",
+            "properties": {
+                "asset_url": {
+                    "type": "string"
+                },
+                "available_from": {
+                    "type": "string"
+                },
+                "available_until": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rarity": {
+                    "$ref": "#/definitions/CosmeticRarity"
+                },
+                "slot": {
+                    "$ref": "#/definitions/CosmeticSlot"
+                },
+                "unlock_rule": {
+                    "$ref": "#/definitions/CosmeticUnlockRule"
+                },
+                "unlock_threshold": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DuckEdit": {
+            "type": "object",
+            "properties": {
+                "appearance": {
+                    "$ref": "#/definitions/DuckAppearance"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duck_id": {
+                    "type": "integer"
+                },
+                "editor_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "previous_appearance": {
+                    "$ref": "#/definitions/DuckAppearance"
+                },
+                "previous_image": {
+                    "type": "string"
+                },
+                "previous_name": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "AdminKey": {
+            "description": "Shared admin key configured through ADMIN_API_KEY.",
+            "type": "apiKey",
+            "name": "X-Admin-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Type \"Bearer\" followed by a space and JWT token.",
             "type": "apiKey",
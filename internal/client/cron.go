@@ -5,23 +5,51 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/omidnikrah/duckparty-backend/internal/model"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
+	leaderboardService "github.com/omidnikrah/duckparty-backend/internal/service/leaderboard"
+	"github.com/omidnikrah/duckparty-backend/internal/storage"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"github.com/omidnikrah/duckparty-backend/internal/websocket"
 	"gorm.io/gorm"
 )
 
 const (
 	leaderboardJobInterval = 4 * time.Hour
 	leaderboardJobTimeout  = 5 * time.Minute
+	// Snapshots are taken on the first run after a period ends, so this
+	// bounds how late a finished period's winners show up.
+	leaderboardSnapshotsJobInterval = 1 * time.Hour
+	leaderboardSnapshotsJobTimeout  = 5 * time.Minute
 )
 
-func NewCron(ctx context.Context, db *gorm.DB, logger *slog.Logger) (gocron.Scheduler, error) {
+type cronJob struct {
+	name           string
+	interval       time.Duration
+	timeout        time.Duration
+	run            func(ctx context.Context) (int64, error)
+	failureMessage string
+	successMessage string
+	idleMessage    string
+}
+
+func NewCron(ctx context.Context, db *gorm.DB, fileStorage storage.Storage, broadcaster *websocket.SocketBroadcaster, reactions *types.ReactionSet, logger *slog.Logger) (gocron.Scheduler, error) {
 	if db == nil {
 		return nil, fmt.Errorf("db is required")
 	}
 
+	if fileStorage == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+
+	if reactions == nil {
+		return nil, fmt.Errorf("reaction set is required")
+	}
+
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -30,8 +58,6 @@ func NewCron(ctx context.Context, db *gorm.DB, logger *slog.Logger) (gocron.Sche
 		logger = slog.Default()
 	}
 
-	leaderboardLogger := logger.With("scope", "cron", "job", "duck-leaderboard")
-
 	scheduler, err := gocron.NewScheduler(
 		gocron.WithLocation(time.UTC),
 		gocron.WithLimitConcurrentJobs(1, gocron.LimitModeWait),
@@ -40,94 +66,185 @@ func NewCron(ctx context.Context, db *gorm.DB, logger *slog.Logger) (gocron.Sche
 		return nil, fmt.Errorf("create scheduler: %w", err)
 	}
 
+	cosmetics := cosmeticService.NewService(db, reactions)
+	leaderboards := leaderboardService.NewService(db, reactions)
+
+	jobs := []cronJob{
+		{
+			name:     "duck-leaderboard",
+			interval: leaderboardJobInterval,
+			timeout:  leaderboardJobTimeout,
+			run: func(ctx context.Context) (int64, error) {
+				changes, err := updateDuckLeaderboard(ctx, db, reactions)
+				if err != nil {
+					return 0, err
+				}
+
+				broadcastLeaderboardChanges(broadcaster, changes)
+
+				if _, err := cosmetics.GrantLeaderboardUnlocks(ctx); err != nil {
+					logger.Error("failed to grant leaderboard cosmetics", "error", err)
+				}
+
+				return int64(len(changes)), nil
+			},
+			failureMessage: "failed to reconcile leaderboard",
+			successMessage: "leaderboard synchronized",
+			idleMessage:    "leaderboard already up to date",
+		},
+		{
+			name:     "leaderboard-snapshots",
+			interval: leaderboardSnapshotsJobInterval,
+			timeout:  leaderboardSnapshotsJobTimeout,
+			run: func(ctx context.Context) (int64, error) {
+				return leaderboards.SnapshotFinishedPeriods(ctx)
+			},
+			failureMessage: "failed to save leaderboard snapshots",
+			successMessage: "leaderboard snapshots saved",
+			idleMessage:    "leaderboard snapshots already up to date",
+		},
+		{
+			name:     "reaction-counts",
+			interval: reactionCountsJobInterval,
+			timeout:  reactionCountsJobTimeout,
+			run: func(ctx context.Context) (int64, error) {
+				return reconcileReactionCounts(ctx, db)
+			},
+			failureMessage: "failed to reconcile reaction counts",
+			successMessage: "reaction counts reconciled",
+			idleMessage:    "reaction counts already up to date",
+		},
+		{
+			name:     "removed-ducks",
+			interval: removedDucksJobInterval,
+			timeout:  removedDucksJobTimeout,
+			run: func(ctx context.Context) (int64, error) {
+				return purgeRemovedDucks(ctx, db, fileStorage)
+			},
+			failureMessage: "failed to purge removed ducks",
+			successMessage: "removed ducks purged",
+			idleMessage:    "no removed ducks to purge",
+		},
+		{
+			name:     "orphaned-duck-images",
+			interval: orphanedDuckImagesJobInterval,
+			timeout:  orphanedDuckImagesJobTimeout,
+			run: func(ctx context.Context) (int64, error) {
+				return sweepOrphanedDuckImages(ctx, db, fileStorage)
+			},
+			failureMessage: "failed to sweep orphaned duck images",
+			successMessage: "orphaned duck images deleted",
+			idleMessage:    "no orphaned duck images found",
+		},
+	}
+
+	for _, job := range jobs {
+		if err := scheduleJob(ctx, scheduler, logger, job); err != nil {
+			return nil, err
+		}
+	}
+
+	scheduler.Start()
+
+	logger.With("scope", "cron").Info("scheduler started", "jobs", len(jobs))
+
+	return scheduler, nil
+}
+
+func scheduleJob(ctx context.Context, scheduler gocron.Scheduler, logger *slog.Logger, job cronJob) error {
+	jobLogger := logger.With("scope", "cron", "job", job.name)
+
 	task := gocron.NewTask(func(jobCtx context.Context) {
 		if ctx.Err() != nil {
 			return
 		}
 
-		execCtx, cancel := context.WithTimeout(jobCtx, leaderboardJobTimeout)
+		execCtx, cancel := context.WithTimeout(jobCtx, job.timeout)
 		defer cancel()
 
-		updated, err := updateDuckLeaderboard(execCtx, db)
+		updated, err := job.run(execCtx)
 		if err != nil {
 			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
-				leaderboardLogger.Error("failed to reconcile leaderboard", "error", err)
+				jobLogger.Error(job.failureMessage, "error", err)
 			}
 			return
 		}
 
 		if updated > 0 {
-			leaderboardLogger.Info("leaderboard synchronized", "rows", updated)
+			jobLogger.Info(job.successMessage, "rows", updated)
 			return
 		}
 
-		leaderboardLogger.Debug("leaderboard already up to date")
+		jobLogger.Debug(job.idleMessage)
 	})
 
 	if _, err := scheduler.NewJob(
-		gocron.DurationJob(leaderboardJobInterval),
+		gocron.DurationJob(job.interval),
 		task,
-		gocron.WithName("duck-leaderboard"),
+		gocron.WithName(job.name),
 		gocron.WithSingletonMode(gocron.LimitModeWait),
 	); err != nil {
-		return nil, fmt.Errorf("schedule leaderboard job: %w", err)
+		return fmt.Errorf("schedule %s job: %w", job.name, err)
 	}
 
-	scheduler.Start()
-
-	leaderboardLogger.Info("scheduler started", "interval", leaderboardJobInterval.String())
+	jobLogger.Info("job scheduled", "interval", job.interval.String())
 
-	return scheduler, nil
+	return nil
 }
 
-func updateDuckLeaderboard(ctx context.Context, db *gorm.DB) (int64, error) {
-	type duckRank struct {
-		ID            uint
-		LikesCount    int64
-		DislikesCount int64
-		Rank          uint
-	}
-
-	var ducks []duckRank
-	if err := db.WithContext(ctx).
-		Model(&model.Duck{}).
-		Select("id", "likes_count", "dislikes_count", "rank").
-		Order("likes_count DESC").
-		Order("dislikes_count ASC").
-		Order("id ASC").
-		Find(&ducks).Error; err != nil {
-		return 0, fmt.Errorf("fetch ducks for leaderboard: %w", err)
+// updateDuckLeaderboard ranks every duck in a single statement and returns
+// the ducks whose rank changed. Ducks with more positive reactions rank
+// higher; fewer negative reactions break ties.
+func updateDuckLeaderboard(ctx context.Context, db *gorm.DB, reactions *types.ReactionSet) ([]websocket.RankChange, error) {
+	var changes []websocket.RankChange
+
+	if err := db.WithContext(ctx).Raw(`UPDATE ducks SET rank = ranked.rank
+		FROM (
+			SELECT ranking.id, ranking.rank AS previous_rank,
+				ROW_NUMBER() OVER (ORDER BY ? DESC, ? ASC, ranking.id ASC) AS rank
+			FROM ducks AS ranking
+			WHERE ranking.deleted_at IS NULL
+		) AS ranked
+		WHERE ducks.id = ranked.id AND ducks.rank <> ranked.rank
+		RETURNING ducks.id AS duck_id, ranked.previous_rank, ducks.rank`,
+		reactions.PositiveCount("ranking.reaction_counts"),
+		reactions.NegativeCount("ranking.reaction_counts"),
+	).Scan(&changes).Error; err != nil {
+		return nil, fmt.Errorf("update duck ranks: %w", err)
 	}
 
-	if len(ducks) == 0 {
-		return 0, nil
-	}
-
-	var updated int64
-
-	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		tx = tx.WithContext(ctx)
+	// RETURNING has no order of its own.
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Rank < changes[j].Rank
+	})
 
-		for index, duck := range ducks {
-			expectedRank := uint(index + 1)
-			if duck.Rank == expectedRank {
-				continue
-			}
+	return changes, nil
+}
 
-			if err := tx.Model(&model.Duck{}).
-				Where("id = ?", duck.ID).
-				Update("rank", expectedRank).Error; err != nil {
-				return err
-			}
+// broadcastLeaderboardChanges only announces moves that are visible on the
+// leaderboard, so a shuffle deep in the ranking does not flood clients.
+func broadcastLeaderboardChanges(broadcaster *websocket.SocketBroadcaster, changes []websocket.RankChange) {
+	if broadcaster == nil {
+		return
+	}
 
-			updated++
+	visible := make([]websocket.RankChange, 0, len(changes))
+	for _, change := range changes {
+		if isLeaderboardRank(change.Rank) || isLeaderboardRank(change.PreviousRank) {
+			visible = append(visible, change)
 		}
+	}
 
-		return nil
-	})
-	if err != nil {
-		return 0, fmt.Errorf("update duck ranks: %w", err)
+	if len(visible) == 0 {
+		return
 	}
 
-	return updated, nil
+	notification := websocket.NewNotification(websocket.NotificationTypeLeaderboardUpdated, websocket.LeaderboardUpdatedData{
+		Changes: visible,
+	}, websocket.TopicLeaderboard)
+	broadcaster.Broadcast(notification)
+}
+
+func isLeaderboardRank(rank uint) bool {
+	return rank > 0 && rank <= model.LeaderboardSize
 }
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	orphanedDuckImagesJobInterval = 24 * time.Hour
+	orphanedDuckImagesJobTimeout  = 30 * time.Minute
+	// Fresh uploads may belong to a duck whose transaction has not committed
+	// yet, so the sweeper leaves anything younger than this alone.
+	orphanedDuckImageMinAge = 1 * time.Hour
+)
+
+var errUnknownImageURL = errors.New("duck image url is not served by the configured storage")
+
+// sweepOrphanedDuckImages deletes stored duck images that no duck, duck
+// edit or leaderboard snapshot points to, e.g. uploads left behind by a failed create.
+func sweepOrphanedDuckImages(ctx context.Context, db *gorm.DB, fileStorage storage.Storage) (int64, error) {
+	files, err := fileStorage.ListFiles(ctx, storage.DuckImageKeyPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("list duck images: %w", err)
+	}
+
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	knownKeys := make(map[string]struct{})
+
+	// A stored URL that does not map to a key could be hiding a live image,
+	// so the sweep stops rather than risk deleting it.
+	addKnownKey := func(url string) error {
+		key, ok := storage.KeyFromURL(fileStorage, url)
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownImageURL, url)
+		}
+
+		knownKeys[key] = struct{}{}
+		return nil
+	}
+
+	var ducks []model.Duck
+	if err := db.WithContext(ctx).
+		Unscoped().
+		Select("id", "image", "image_medium", "image_thumbnail").
+		Where("images_purged_at IS NULL").
+		FindInBatches(&ducks, 1000, func(tx *gorm.DB, batch int) error {
+			for _, duck := range ducks {
+				for _, url := range duck.ImageURLs() {
+					if err := addKnownKey(url); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}).Error; err != nil {
+		return 0, fmt.Errorf("fetch duck images: %w", err)
+	}
+
+	var keptImages []string
+	if err := db.WithContext(ctx).
+		Raw(`SELECT previous_image FROM duck_edits WHERE previous_image <> ''
+			UNION SELECT image FROM duck_edits WHERE image <> ''
+			UNION SELECT duck_image FROM leaderboard_snapshot_entries WHERE duck_image <> ''`).
+		Scan(&keptImages).Error; err != nil {
+		return 0, fmt.Errorf("fetch duck edit and leaderboard images: %w", err)
+	}
+
+	for _, url := range keptImages {
+		if err := addKnownKey(url); err != nil {
+			return 0, fmt.Errorf("fetch duck edit and leaderboard images: %w", err)
+		}
+	}
+
+	cutoff := time.Now().Add(-orphanedDuckImageMinAge)
+
+	var deleted int64
+
+	for _, file := range files {
+		if file.LastModified.After(cutoff) {
+			continue
+		}
+
+		if _, ok := knownKeys[file.Key]; ok {
+			continue
+		}
+
+		if err := fileStorage.DeleteFile(ctx, file.Key); err != nil {
+			return deleted, fmt.Errorf("delete orphaned image %s: %w", file.Key, err)
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
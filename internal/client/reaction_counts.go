@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	reactionCountsJobInterval = 6 * time.Hour
+	reactionCountsJobTimeout  = 10 * time.Minute
+	reactionCountsBatchSize   = 500
+)
+
+// storedReactionCounts rebuilds a duck's reaction_counts from its rows in
+// duck_reactions.
+const storedReactionCounts = `COALESCE((
+	SELECT jsonb_object_agg(counts.reaction, counts.count) FROM (
+		SELECT reaction, COUNT(*) AS count FROM duck_reactions
+		WHERE duck_reactions.duck_id = ducks.id GROUP BY reaction
+	) AS counts
+), '{}'::jsonb)`
+
+// reconcileReactionCounts fixes ducks whose reaction counts drifted from the
+// reactions actually stored. Each batch locks its ducks before recounting,
+// so a reaction committed meanwhile is either counted here or applies its
+// own increment afterwards, never both.
+func reconcileReactionCounts(ctx context.Context, db *gorm.DB) (int64, error) {
+	var drifted []uint
+	if err := db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Duck{}).
+		Where("reaction_counts IS DISTINCT FROM "+storedReactionCounts).
+		Order("id").
+		Pluck("id", &drifted).Error; err != nil {
+		return 0, fmt.Errorf("find drifted reaction counts: %w", err)
+	}
+
+	var fixed int64
+
+	for start := 0; start < len(drifted); start += reactionCountsBatchSize {
+		batch := drifted[start:min(start+reactionCountsBatchSize, len(drifted))]
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var locked []uint
+			if err := tx.Raw(`SELECT id FROM ducks WHERE id IN ? ORDER BY id FOR UPDATE`, batch).Scan(&locked).Error; err != nil {
+				return err
+			}
+
+			result := tx.Exec(`UPDATE ducks SET reaction_counts = `+storedReactionCounts+`
+				WHERE id IN ? AND reaction_counts IS DISTINCT FROM `+storedReactionCounts, batch)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			fixed += result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			return fixed, fmt.Errorf("recount reactions: %w", err)
+		}
+	}
+
+	return fixed, nil
+}
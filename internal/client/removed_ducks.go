@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	removedDucksJobInterval = 1 * time.Hour
+	removedDucksJobTimeout  = 10 * time.Minute
+	removedDucksBatchSize   = 500
+)
+
+// purgeRemovedDucks permanently deletes ducks that have been in the trash
+// for longer than model.RemovedDuckRetention, along with their reactions,
+// edit history and stored images. Leaderboard snapshots keep the duck's
+// name and image.
+func purgeRemovedDucks(ctx context.Context, db *gorm.DB, fileStorage storage.Storage) (int64, error) {
+	var ducks []model.Duck
+	if err := db.WithContext(ctx).
+		Unscoped().
+		Select("id", "image", "image_medium", "image_thumbnail", "images_purged_at").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", time.Now().Add(-model.RemovedDuckRetention)).
+		Order("deleted_at ASC").
+		Limit(removedDucksBatchSize).
+		Find(&ducks).Error; err != nil {
+		return 0, fmt.Errorf("fetch removed ducks: %w", err)
+	}
+
+	var purged int64
+
+	for _, duck := range ducks {
+		// Images go first: if deleting them fails the duck is kept and
+		// retried on the next run rather than leaving unreferenced files.
+		// images_purged_at saves a retry from deleting them again when only
+		// the rows failed.
+		if duck.ImagesPurgedAt == nil {
+			if err := deleteDuckImages(ctx, db, fileStorage, duck); err != nil {
+				return purged, err
+			}
+
+			if err := db.WithContext(ctx).
+				Unscoped().
+				Model(&model.Duck{}).
+				Where("id = ?", duck.ID).
+				Update("images_purged_at", time.Now()).Error; err != nil {
+				return purged, fmt.Errorf("mark images of duck %d as purged: %w", duck.ID, err)
+			}
+		}
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("duck_id = ?", duck.ID).Delete(&model.DuckReactions{}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("duck_id = ?", duck.ID).Delete(&model.DuckEdit{}).Error; err != nil {
+				return err
+			}
+
+			return tx.Unscoped().Delete(&model.Duck{}, duck.ID).Error
+		})
+		if err != nil {
+			return purged, fmt.Errorf("delete duck %d: %w", duck.ID, err)
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// deleteDuckImages deletes every stored image of duck. Images replaced by
+// edits are kept for the edit history, so they go with the duck. Images
+// shown by leaderboard snapshots stay.
+func deleteDuckImages(ctx context.Context, db *gorm.DB, fileStorage storage.Storage, duck model.Duck) error {
+	var editImages []string
+	if err := db.WithContext(ctx).
+		Raw(`SELECT previous_image FROM duck_edits WHERE duck_id = ? AND previous_image <> ''
+			UNION SELECT image FROM duck_edits WHERE duck_id = ? AND image <> ''`, duck.ID, duck.ID).
+		Scan(&editImages).Error; err != nil {
+		return fmt.Errorf("fetch edit images of duck %d: %w", duck.ID, err)
+	}
+
+	var snapshotImages []string
+	if err := db.WithContext(ctx).
+		Model(&model.LeaderboardSnapshotEntry{}).
+		Where("duck_id = ? AND duck_image <> ''", duck.ID).
+		Distinct().
+		Pluck("duck_image", &snapshotImages).Error; err != nil {
+		return fmt.Errorf("fetch leaderboard images of duck %d: %w", duck.ID, err)
+	}
+
+	kept := make(map[string]struct{}, len(snapshotImages))
+	for _, url := range snapshotImages {
+		kept[url] = struct{}{}
+	}
+
+	for _, url := range append(duck.ImageURLs(), editImages...) {
+		if _, ok := kept[url]; ok {
+			continue
+		}
+
+		key, ok := storage.KeyFromURL(fileStorage, url)
+		if !ok {
+			continue
+		}
+
+		if err := fileStorage.DeleteFile(ctx, key); err != nil {
+			return fmt.Errorf("delete image of duck %d: %w", duck.ID, err)
+		}
+	}
+
+	return nil
+}
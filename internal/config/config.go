@@ -2,54 +2,132 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
 )
 
 type Config struct {
-	AppPort            string
-	DBHost             string
-	DBPort             string
-	DBUser             string
-	DBPassword         string
-	DBName             string
-	R2AccountID        string
-	R2Bucket           string
-	R2BaseURL          string
-	R2AccessKeyID      string
-	R2SecretAccessKey  string
-	RedisHost          string
-	RedisPassword      string
-	RedisPort          string
-	JWTSecret          string
-	AuthSenderEmail    string
-	ResendAPIKey       string
-	ApiPrefix          string
+	AppPort           string
+	DBHost            string
+	DBPort            string
+	DBUser            string
+	DBPassword        string
+	DBName            string
+	StorageDriver     string
+	StorageLocalDir   string
+	StoragePublicURL  string
+	R2AccountID       string
+	R2Bucket          string
+	R2BaseURL         string
+	R2AccessKeyID     string
+	R2SecretAccessKey string
+	RedisHost         string
+	RedisPassword     string
+	RedisPort         string
+	JWTSecret         string
+	AuthSenderEmail   string
+	ResendAPIKey      string
+	ApiPrefix         string
+	WSAllowedOrigins  []string
+	WSMaxConnsPerUser int
+	WSHistorySize     int
+	AdminAPIKey       string
+	SpritesDir        string
+	AllowImageUploads bool
+	Reactions         *types.ReactionSet
 }
 
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
+	appPort := os.Getenv("APP_PORT")
+
 	config := &Config{
-		AppPort:            os.Getenv("APP_PORT"),
-		ApiPrefix:          os.Getenv("API_PREFIX"),
-		DBHost:             os.Getenv("DB_HOST"),
-		DBPort:             os.Getenv("DB_PORT"),
-		DBUser:             os.Getenv("DB_USER"),
-		DBPassword:         os.Getenv("DB_PASSWORD"),
-		DBName:             os.Getenv("DB_NAME"),
-		R2AccountID:        os.Getenv("R2_ACCOUNT_ID"),
-		R2Bucket:           os.Getenv("R2_BUCKET"),
-		R2BaseURL:          os.Getenv("R2_BASE_URL"),
-		R2AccessKeyID:      os.Getenv("R2_ACCESS_KEY_ID"),
-		R2SecretAccessKey:  os.Getenv("R2_SECRET_ACCESS_KEY"),
-		RedisHost:          os.Getenv("REDIS_HOST"),
-		RedisPort:          os.Getenv("REDIS_PORT"),
-		RedisPassword:      os.Getenv("REDIS_PASSWORD"),
-		JWTSecret:          os.Getenv("JWT_SECRET"),
-		AuthSenderEmail:    os.Getenv("AUTH_SENDER_EMAIL"),
-		ResendAPIKey:       os.Getenv("RESEND_API_KEY"),
+		AppPort:           appPort,
+		ApiPrefix:         os.Getenv("API_PREFIX"),
+		DBHost:            os.Getenv("DB_HOST"),
+		DBPort:            os.Getenv("DB_PORT"),
+		DBUser:            os.Getenv("DB_USER"),
+		DBPassword:        os.Getenv("DB_PASSWORD"),
+		DBName:            os.Getenv("DB_NAME"),
+		StorageDriver:     getEnv("STORAGE_DRIVER", "r2"),
+		StorageLocalDir:   getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		StoragePublicURL:  getEnv("STORAGE_PUBLIC_URL", "http://localhost:"+appPort+"/uploads"),
+		R2AccountID:       os.Getenv("R2_ACCOUNT_ID"),
+		R2Bucket:          os.Getenv("R2_BUCKET"),
+		R2BaseURL:         os.Getenv("R2_BASE_URL"),
+		R2AccessKeyID:     os.Getenv("R2_ACCESS_KEY_ID"),
+		R2SecretAccessKey: os.Getenv("R2_SECRET_ACCESS_KEY"),
+		RedisHost:         os.Getenv("REDIS_HOST"),
+		RedisPort:         os.Getenv("REDIS_PORT"),
+		RedisPassword:     os.Getenv("REDIS_PASSWORD"),
+		JWTSecret:         os.Getenv("JWT_SECRET"),
+		AuthSenderEmail:   os.Getenv("AUTH_SENDER_EMAIL"),
+		ResendAPIKey:      os.Getenv("RESEND_API_KEY"),
+		WSAllowedOrigins:  getEnvList("WS_ALLOWED_ORIGINS"),
+		WSMaxConnsPerUser: getEnvInt("WS_MAX_CONNECTIONS_PER_USER", 5),
+		WSHistorySize:     getEnvInt("WS_HISTORY_SIZE", 1000),
+		AdminAPIKey:       os.Getenv("ADMIN_API_KEY"),
+		SpritesDir:        getEnv("SPRITES_DIR", "./assets/sprites"),
+		AllowImageUploads: getEnvBool("ALLOW_IMAGE_UPLOADS", true),
+	}
+
+	reactions, err := types.NewReactionSet(
+		getEnvListOr("REACTION_TYPES", types.DefaultReactionTypes),
+		getEnvListOr("NEGATIVE_REACTION_TYPES", types.DefaultNegativeReactionTypes),
+	)
+	if err != nil {
+		return nil, err
 	}
+	config.Reactions = reactions
 
 	return config, nil
 }
+
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+func getEnvList(key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func getEnvListOr(key string, fallback []string) []string {
+	if _, ok := os.LookupEnv(key); !ok {
+		return fallback
+	}
+
+	return getEnvList(key)
+}
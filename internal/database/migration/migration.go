@@ -4,7 +4,10 @@ import (
 	"fmt"
 
 	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/placement"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func Migrate(db *gorm.DB) error {
@@ -13,6 +16,46 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("❌ Failed to up migration: %w", err)
 	}
 
+	if err := backfillDuckPositions(db); err != nil {
+		return fmt.Errorf("❌ Failed to backfill duck positions: %w", err)
+	}
+
+	return nil
+}
+
+// backfillDuckPositions spreads out ducks created before placement existed,
+// which were all stacked at 0,0.
+func backfillDuckPositions(db *gorm.DB) error {
+	var stacked []model.Duck
+	if err := db.Unscoped().Select("id").Where("x = 0 AND y = 0").Order("id").Find(&stacked).Error; err != nil {
+		return err
+	}
+
+	if len(stacked) < 2 {
+		return nil
+	}
+
+	var points []placement.Point
+	if err := db.Unscoped().Model(&model.Duck{}).Where("NOT (x = 0 AND y = 0)").Select("x", "y").Find(&points).Error; err != nil {
+		return err
+	}
+
+	grid := placement.NewGrid(points)
+
+	fmt.Printf("🔄 Placing %d ducks stacked at 0,0\n", len(stacked))
+	for _, duck := range stacked {
+		position, err := grid.FindFree()
+		if err != nil {
+			return err
+		}
+		grid.Insert(position)
+
+		if err := db.Unscoped().Model(&model.Duck{}).Where("id = ?", duck.ID).UpdateColumns(map[string]interface{}{"x": position.X, "y": position.Y}).Error; err != nil {
+			return err
+		}
+	}
+	fmt.Printf("✅ Placed %d ducks\n", len(stacked))
+
 	return nil
 }
 
@@ -34,13 +77,125 @@ func Up(db *gorm.DB) error {
 		&model.User{},
 		&model.Duck{},
 		&model.DuckReactions{},
+		&model.DuckEdit{},
+		&model.Cosmetic{},
+		&model.UserCosmetic{},
+		&model.LeaderboardSnapshotEntry{},
+		&model.LeaderboardSnapshotPeriod{},
+	}
+
+	if err := PerformMigration(db, models...); err != nil {
+		return err
+	}
+
+	if err := migrateReactionCounts(db); err != nil {
+		return err
+	}
+
+	if err := migrateSnapshotDucks(db); err != nil {
+		return err
+	}
+
+	return seedCosmetics(db)
+}
+
+// migrateReactionCounts moves ducks from the old likes_count and
+// dislikes_count columns to the reaction_counts map, counting every stored
+// reaction.
+func migrateReactionCounts(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&model.Duck{}, "likes_count") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE ducks SET reaction_counts = COALESCE((
+			SELECT jsonb_object_agg(counts.reaction, counts.count) FROM (
+				SELECT reaction, COUNT(*) AS count FROM duck_reactions
+				WHERE duck_reactions.duck_id = ducks.id GROUP BY reaction
+			) AS counts
+		), '{}'::jsonb)`).Error; err != nil {
+			return err
+		}
+
+		for _, column := range []string{"likes_count", "dislikes_count"} {
+			if err := tx.Migrator().DropColumn(&model.Duck{}, column); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// migrateSnapshotDucks keeps leaderboard snapshots of deleted ducks: their
+// entries used to be deleted along with the duck, and now only lose the
+// link to it. Entries saved before the duck name and image were stored get
+// them from the duck, and their periods are recorded as saved.
+func migrateSnapshotDucks(db *gorm.DB) error {
+	var deleteRule string
+	if err := db.Raw(`SELECT delete_rule FROM information_schema.referential_constraints
+		WHERE constraint_schema = CURRENT_SCHEMA() AND constraint_name = ?`, "fk_leaderboard_snapshot_entries_duck").
+		Scan(&deleteRule).Error; err != nil {
+		return err
+	}
+
+	if deleteRule == "CASCADE" {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropConstraint(&model.LeaderboardSnapshotEntry{}, "Duck"); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateConstraint(&model.LeaderboardSnapshotEntry{}, "Duck")
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(`UPDATE leaderboard_snapshot_entries SET duck_name = ducks.name, duck_image = ducks.image
+		FROM ducks
+		WHERE ducks.id = leaderboard_snapshot_entries.duck_id AND leaderboard_snapshot_entries.duck_name = ''`).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`INSERT INTO leaderboard_snapshot_periods (period, starts_at, ends_at, created_at)
+		SELECT DISTINCT period, starts_at, ends_at, NOW() FROM leaderboard_snapshot_entries
+		ON CONFLICT DO NOTHING`).Error
+}
+
+// defaultCosmetics are the skins and accessories that existed as Go
+// constants before the catalog moved to the database. Cosmetics without an
+// unlock rule are free.
+var defaultCosmetics = []model.Cosmetic{
+	{ID: string(types.SkinGiraffe), Slot: types.SlotSkin, DisplayName: "Giraffe", Rarity: model.RarityCommon},
+	{ID: string(types.SkinLGBT), Slot: types.SlotSkin, DisplayName: "Rainbow", Rarity: model.RarityCommon},
+	{ID: string(types.SkinSuperman), Slot: types.SlotSkin, DisplayName: "Superman", Rarity: model.RarityRare},
+	{ID: string(types.AccessoryFlowerCrown), Slot: types.SlotHead, DisplayName: "Flower Crown", Rarity: model.RarityCommon},
+	{ID: string(types.AccessoryKingCrown), Slot: types.SlotHead, DisplayName: "King Crown", Rarity: model.RarityEpic, UnlockRule: model.UnlockTopLeaderboard, UnlockThreshold: 10},
+	{ID: string(types.AccessoryVespaHelmet), Slot: types.SlotHead, DisplayName: "Vespa Helmet", Rarity: model.RarityRare, UnlockRule: model.UnlockLikesReceived, UnlockThreshold: 25},
+	{ID: string(types.AccessorySupermanCape), Slot: types.SlotBack, DisplayName: "Superman Cape", Rarity: model.RarityRare, UnlockRule: model.UnlockFirstDuck},
+}
+
+// seedCosmetics adds missing default cosmetics without touching ones that
+// were edited or retired since.
+func seedCosmetics(db *gorm.DB) error {
+	cosmetics := make([]model.Cosmetic, len(defaultCosmetics))
+	copy(cosmetics, defaultCosmetics)
+	for i := range cosmetics {
+		if cosmetics[i].UnlockRule == "" {
+			cosmetics[i].UnlockRule = model.UnlockFree
+		}
 	}
 
-	return PerformMigration(db, models...)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cosmetics).Error
 }
 
 func Down(db *gorm.DB) error {
 	models := []interface{}{
+		&model.LeaderboardSnapshotPeriod{},
+		&model.LeaderboardSnapshotEntry{},
+		&model.UserCosmetic{},
+		&model.Cosmetic{},
+		&model.DuckEdit{},
 		&model.DuckReactions{},
 		&model.Duck{},
 		&model.User{},
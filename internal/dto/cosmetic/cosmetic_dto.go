@@ -0,0 +1,17 @@
+package cosmetic_dto
+
+import "time"
+
+type CreateCosmeticDTO struct {
+	ID             string     `json:"id" binding:"required,max=64" example:"party_hat"`
+	Slot           string     `json:"slot" binding:"required,oneof=skin head back" example:"head"`
+	DisplayName    string     `json:"display_name" binding:"required,max=100" example:"Party Hat"`
+	AssetURL       string     `json:"asset_url" binding:"omitempty,url" example:"https://example.com/cosmetics/party_hat.png"`
+	Rarity         string     `json:"rarity" binding:"omitempty,oneof=common rare epic legendary" example:"rare"`
+	AvailableFrom  *time.Time `json:"available_from" example:"2024-01-01T00:00:00Z"`
+	AvailableUntil *time.Time `json:"available_until" example:"2024-02-01T00:00:00Z"`
+	// UnlockRule defaults to free. top_leaderboard and likes_received need
+	// an unlock_threshold.
+	UnlockRule      string `json:"unlock_rule" binding:"omitempty,oneof=free first_duck top_leaderboard likes_received" example:"likes_received"`
+	UnlockThreshold int    `json:"unlock_threshold" binding:"omitempty,min=1" example:"50"`
+} // @name CreateCosmeticRequest
@@ -16,38 +16,160 @@ type CreateDuckDTO struct {
 
 type ReactToDuckDTO struct {
 	DuckId   uint               `json:"duck_id" binding:"required"`
-	Reaction model.ReactionType `json:"reaction" binding:"required"`
+	Reaction types.ReactionType `json:"reaction" binding:"required"`
+}
+
+type MoveDuckDTO struct {
+	X *float64 `json:"x" binding:"required"`
+	Y *float64 `json:"y" binding:"required"`
+}
+
+type DuckListQuery struct {
+	Cursor        string     `form:"cursor"`
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Skin          string     `form:"skin"`
+	Accessory     string     `form:"accessory"`
+	OwnerID       uint       `form:"owner_id"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+}
+
+type DuckListResponse struct {
+	Items      []DuckResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoxfQ"`
+	HasMore    bool           `json:"has_more" example:"true"`
+} // @name DuckListResponse
+
+type TrashedDuckResponse struct {
+	Duck            DuckResponse `json:"duck"`
+	RestorableUntil time.Time    `json:"restorable_until" example:"2024-01-08T00:00:00Z"`
+} // @name TrashedDuckResponse
+
+type DuckViewportQuery struct {
+	MinX  *float64 `form:"minX" binding:"required"`
+	MinY  *float64 `form:"minY" binding:"required"`
+	MaxX  *float64 `form:"maxX" binding:"required"`
+	MaxY  *float64 `form:"maxY" binding:"required"`
+	Limit int      `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type DuckViewportResponse struct {
+	Items []DuckResponse `json:"items"`
+	// Total is how many ducks are inside the viewport.
+	Total int64 `json:"total" example:"42"`
+	// Truncated is true when there were too many ducks to list; Density
+	// then summarizes where they are instead.
+	Truncated bool              `json:"truncated" example:"false"`
+	Density   []DuckDensityCell `json:"density,omitempty"`
+} // @name DuckViewportResponse
+
+type DuckDensityCell struct {
+	MinX  float64 `json:"min_x" example:"0"`
+	MinY  float64 `json:"min_y" example:"0"`
+	MaxX  float64 `json:"max_x" example:"500"`
+	MaxY  float64 `json:"max_y" example:"500"`
+	Count int64   `json:"count" example:"12"`
 }
 
 type DuckResponse struct {
-	ID            uint                 `json:"id" example:"1"`
-	CreatedAt     time.Time            `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt     time.Time            `json:"updated_at" example:"2024-01-01T00:00:00Z"`
-	OwnerID       uint                 `json:"owner_id" example:"1"`
-	Owner         DuckUserResponse     `json:"owner"`
-	Name          string               `json:"name" example:"Ducky"`
-	X             float64              `json:"x" example:"100.5"`
-	Y             float64              `json:"y" example:"200.5"`
-	Appearance    types.DuckAppearance `json:"appearance"`
-	Image         string               `json:"image" example:"https://example.com/image.jpg"`
-	LikesCount    int64                `json:"likes_count" example:"10"`
-	DislikesCount int64                `json:"dislikes_count" example:"2"`
-	Rank          uint                 `json:"rank" example:"1"`
+	ID             uint                 `json:"id" example:"1"`
+	CreatedAt      time.Time            `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt      time.Time            `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	OwnerID        uint                 `json:"owner_id" example:"1"`
+	Owner          *DuckUserResponse    `json:"owner,omitempty"`
+	Name           string               `json:"name" example:"Ducky"`
+	X              float64              `json:"x" example:"100.5"`
+	Y              float64              `json:"y" example:"200.5"`
+	Appearance     types.DuckAppearance `json:"appearance"`
+	Image          string               `json:"image" example:"https://example.com/image.png"`
+	ImageMedium    string               `json:"image_medium" example:"https://example.com/image_medium.png"`
+	ImageThumbnail string               `json:"image_thumbnail" example:"https://example.com/image_thumb.png"`
+	ReactionCounts map[string]int64     `json:"reaction_counts"`
+	LikesCount     int64                `json:"likes_count" example:"10"`
+	DislikesCount  int64                `json:"dislikes_count" example:"2"`
+	Rank           uint                 `json:"rank" example:"1"`
 } // @name DuckResponse
 
+type ReactionTypeResponse struct {
+	Type types.ReactionType `json:"type" example:"quack"`
+	// Negative reactions count against a duck in the leaderboard.
+	Negative bool `json:"negative" example:"false"`
+} // @name ReactionTypeResponse
+
 type DuckUserResponse struct {
 	ID          uint      `json:"id" example:"1"`
 	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
-	Email       string    `json:"email" example:"user@example.com"`
 	DisplayName string    `json:"display_name" example:"John Doe"`
 } // @name DuckUserResponse
 
 type DuckReactionResponse struct {
 	UserID    uint               `json:"user_id" example:"1"`
 	DuckID    uint               `json:"duck_id" example:"1"`
-	Reaction  model.ReactionType `json:"reaction" example:"like" enums:"like,dislike"`
-	User      DuckUserResponse   `json:"user"`
-	Duck      DuckResponse       `json:"duck"`
+	Reaction  types.ReactionType `json:"reaction" example:"quack"`
+	User      *DuckUserResponse  `json:"user,omitempty"`
+	Duck      *DuckResponse      `json:"duck,omitempty"`
 	CreatedAt time.Time          `json:"created_at" example:"2024-01-01T00:00:00Z"`
 } // @name DuckReactionResponse
+
+type DuckReactionListQuery struct {
+	Cursor   string `form:"cursor"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Reaction string `form:"reaction"`
+}
+
+type DuckReactionListResponse struct {
+	Items      []DuckReactionResponse `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpIjoxfQ"`
+	HasMore    bool                   `json:"has_more" example:"true"`
+} // @name DuckReactionListResponse
+
+// NewDuckResponse is the public view of a duck, safe to show to anyone.
+// likes_count and dislikes_count repeat the like and dislike entries of
+// reaction_counts for clients from before custom reactions.
+func NewDuckResponse(duck model.Duck) DuckResponse {
+	counts := make(map[string]int64, len(duck.ReactionCounts))
+	for reaction, count := range duck.ReactionCounts {
+		counts[string(reaction)] = count
+	}
+
+	response := DuckResponse{
+		ID:             duck.ID,
+		CreatedAt:      duck.CreatedAt,
+		UpdatedAt:      duck.UpdatedAt,
+		OwnerID:        duck.OwnerID,
+		Name:           duck.Name,
+		X:              duck.X,
+		Y:              duck.Y,
+		Appearance:     duck.Appearance,
+		Image:          duck.Image,
+		ImageMedium:    duck.ImageMedium,
+		ImageThumbnail: duck.ImageThumbnail,
+		ReactionCounts: counts,
+		LikesCount:     duck.ReactionCounts[types.ReactionLike],
+		DislikesCount:  duck.ReactionCounts[types.ReactionDislike],
+		Rank:           duck.Rank,
+	}
+
+	if duck.Owner.ID != 0 {
+		owner := NewDuckUserResponse(duck.Owner)
+		response.Owner = &owner
+	}
+
+	return response
+}
+
+// NewDuckUserResponse leaves out the email, which is private to the user.
+func NewDuckUserResponse(user model.User) DuckUserResponse {
+	response := DuckUserResponse{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+
+	if user.DisplayName != nil {
+		response.DisplayName = *user.DisplayName
+	}
+
+	return response
+}
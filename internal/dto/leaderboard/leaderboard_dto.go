@@ -0,0 +1,37 @@
+package leaderboard_dto
+
+import (
+	"time"
+
+	duck_dto "github.com/omidnikrah/duckparty-backend/internal/dto/duck"
+)
+
+type LeaderboardQuery struct {
+	Period string `form:"period" binding:"omitempty,oneof=day week month all"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type LeaderboardHistoryQuery struct {
+	Period string `form:"period" binding:"required,oneof=day week month"`
+	// At picks the period containing this time; it defaults to the last
+	// finished period.
+	At    *time.Time `form:"at"`
+	Limit int        `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type LeaderboardResponse struct {
+	Period   string             `json:"period" example:"week" enums:"day,week,month,all"`
+	StartsAt *time.Time         `json:"starts_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	EndsAt   *time.Time         `json:"ends_at,omitempty" example:"2024-01-08T00:00:00Z"`
+	Entries  []LeaderboardEntry `json:"entries"`
+} // @name LeaderboardResponse
+
+type LeaderboardEntry struct {
+	Rank uint `json:"rank" example:"1"`
+	// ReactionCounts are the reactions the duck received during the period.
+	ReactionCounts map[string]int64 `json:"reaction_counts"`
+	DuckName       string           `json:"duck_name" example:"Ducky"`
+	DuckImage      string           `json:"duck_image" example:"https://example.com/image.png"`
+	// Duck is left out for past winners that have since been deleted.
+	Duck *duck_dto.DuckResponse `json:"duck,omitempty"`
+} // @name LeaderboardEntry
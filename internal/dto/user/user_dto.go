@@ -1,6 +1,10 @@
 package user_dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+)
 
 type AuthenticateUserDTO struct {
 	Email string `json:"email" binding:"required,email" example:"user@example.com"`
@@ -33,5 +37,13 @@ type CreateAnonymousUserDTO struct {
 } // @name CreateAnonymousUserRequest
 
 type UserInfoResponse struct {
-	User UserResponse `json:"user"`
+	User      UserResponse            `json:"user"`
+	Inventory []InventoryItemResponse `json:"inventory"`
 } // @name UserInfoResponse
+
+// InventoryItemResponse is a cosmetic the user can use. unlocked_at is
+// omitted for free cosmetics.
+type InventoryItemResponse struct {
+	Cosmetic   model.Cosmetic `json:"cosmetic"`
+	UnlockedAt *time.Time     `json:"unlocked_at,omitempty" example:"2024-01-01T00:00:00Z"`
+} // @name InventoryItemResponse
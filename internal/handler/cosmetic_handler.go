@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	cosmetic_dto "github.com/omidnikrah/duckparty-backend/internal/dto/cosmetic"
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+)
+
+type CosmeticHandler struct {
+	cosmeticService *cosmeticService.CosmeticService
+}
+
+func NewCosmeticHandler(cosmeticService *cosmeticService.CosmeticService) *CosmeticHandler {
+	return &CosmeticHandler{
+		cosmeticService: cosmeticService,
+	}
+}
+
+// GetCosmetics godoc
+// @Summary      Get available cosmetics
+// @Description  Returns the skins and accessories that can currently be used in a duck's appearance
+// @Tags         cosmetics
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   model.Cosmetic     "Available cosmetics"
+// @Failure      500  {object}  map[string]string  "Error message"
+// @Router       /cosmetics [get]
+func (h *CosmeticHandler) GetCosmetics(c *gin.Context) {
+	cosmetics, err := h.cosmeticService.GetAvailableCosmetics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cosmetics)
+}
+
+// GetAllCosmetics godoc
+// @Summary      Get all cosmetics
+// @Description  Returns every cosmetic, including retired and upcoming ones
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Success      200  {array}   model.Cosmetic     "All cosmetics"
+// @Failure      403  {object}  map[string]string  "Forbidden"
+// @Failure      500  {object}  map[string]string  "Error message"
+// @Router       /admin/cosmetics [get]
+func (h *CosmeticHandler) GetAllCosmetics(c *gin.Context) {
+	cosmetics, err := h.cosmeticService.GetAllCosmetics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cosmetics)
+}
+
+// CreateCosmetic godoc
+// @Summary      Add a cosmetic
+// @Description  Adds a skin or accessory to the catalog. It can be used in appearances as soon as its availability window opens.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Param        request  body      cosmetic_dto.CreateCosmeticDTO  true  "Cosmetic"
+// @Success      201      {object}  model.Cosmetic     "Created cosmetic"
+// @Failure      400      {object}  map[string]string  "Error message"
+// @Failure      403      {object}  map[string]string  "Forbidden"
+// @Failure      409      {object}  map[string]string  "Cosmetic already exists"
+// @Failure      500      {object}  map[string]string  "Error message"
+// @Router       /admin/cosmetics [post]
+func (h *CosmeticHandler) CreateCosmetic(c *gin.Context) {
+	var req cosmetic_dto.CreateCosmeticDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.AvailableFrom != nil && req.AvailableUntil != nil && !req.AvailableUntil.After(*req.AvailableFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "available_until must be after available_from"})
+		return
+	}
+
+	cosmetic, err := h.cosmeticService.CreateCosmetic(cosmeticService.CreateCosmeticRequest{
+		ID:              req.ID,
+		Slot:            types.CosmeticSlot(req.Slot),
+		DisplayName:     req.DisplayName,
+		AssetURL:        req.AssetURL,
+		Rarity:          model.CosmeticRarity(req.Rarity),
+		AvailableFrom:   req.AvailableFrom,
+		AvailableUntil:  req.AvailableUntil,
+		UnlockRule:      model.CosmeticUnlockRule(req.UnlockRule),
+		UnlockThreshold: req.UnlockThreshold,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, cosmeticService.ErrMissingUnlockThreshold):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, cosmeticService.ErrCosmeticExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, cosmetic)
+}
+
+// RetireCosmetic godoc
+// @Summary      Retire a cosmetic
+// @Description  Closes the availability window of a cosmetic now. Ducks already wearing it keep it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Param        cosmeticId  path      string  true  "Cosmetic ID"
+// @Success      200         {object}  model.Cosmetic     "Retired cosmetic"
+// @Failure      403         {object}  map[string]string  "Forbidden"
+// @Failure      404         {object}  map[string]string  "Cosmetic not found"
+// @Failure      500         {object}  map[string]string  "Error message"
+// @Router       /admin/cosmetics/{cosmeticId} [delete]
+func (h *CosmeticHandler) RetireCosmetic(c *gin.Context) {
+	cosmetic, err := h.cosmeticService.RetireCosmetic(c.Param("cosmeticId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, cosmeticService.ErrCosmeticNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, cosmetic)
+}
@@ -7,9 +7,16 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	duck_dto "github.com/omidnikrah/duckparty-backend/internal/dto/duck"
+	"github.com/omidnikrah/duckparty-backend/internal/imaging"
 	"github.com/omidnikrah/duckparty-backend/internal/middleware"
 	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/placement"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
 	duckService "github.com/omidnikrah/duckparty-backend/internal/service/duck"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"github.com/omidnikrah/duckparty-backend/internal/utils"
 )
 
 type DuckHandler struct {
@@ -24,45 +31,47 @@ func NewDuckHandler(duckService *duckService.DuckService) *DuckHandler {
 
 // CreateDuck godoc
 // @Summary      Create a new duck
-// @Description  Creates a new duck with image, name, email, and appearance data
+// @Description  Creates a new duck with name and appearance data. Without an image, the duck image is rendered on the server from its appearance. Uploaded images, where allowed, must be a PNG, JPEG, GIF or WebP of at most 5 MiB and 2048x2048px; every image is stored as PNG alongside medium and thumbnail variants.
 // @Tags         ducks
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
-// @Param        image       formData  file    true   "Duck image file"
+// @Param        image       formData  file    false  "Duck image file; rejected when uploads are disabled"
 // @Param        name        formData  string  true   "Duck name"
 // @Param        appearance  formData  string  true   "Duck appearance JSON"
+// @Param        x           formData  number  false  "X position on the party canvas; omit x and y to auto-place"
+// @Param        y           formData  number  false  "Y position on the party canvas; omit x and y to auto-place"
 // @Success      200         {object}  duck_dto.DuckResponse  "Created duck"
 // @Failure      400         {object}  map[string]string  "Error message"
+// @Failure      403         {object}  map[string]string  "Appearance uses a cosmetic the owner has not unlocked"
+// @Failure      409         {object}  map[string]string  "No free space left on the canvas"
+// @Failure      413         {object}  map[string]string  "Image too large"
 // @Failure      500         {object}  map[string]string  "Error message"
+// @Failure      503         {object}  map[string]string  "Duck image cannot be rendered"
 // @Router       /duck [post]
 func (h *DuckHandler) CreateDuck(c *gin.Context) {
+	if !parseDuckForm(c) {
+		return
+	}
+
 	name := c.PostForm("name")
 	appearanceJSON := c.PostForm("appearance")
 
 	user, _ := middleware.GetAuthUser(c)
 
-	file, err := c.FormFile("image")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "image file is required"})
-		return
-	}
-
 	if name == "" || appearanceJSON == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "name and appearance are required"})
 		return
 	}
 
-	src, err := file.Open()
+	position, err := parsePosition(c.PostForm("x"), c.PostForm("y"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open uploaded file: " + err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer src.Close()
 
-	fileContent, err := io.ReadAll(src)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file content: " + err.Error()})
+	fileContent, ok := readOptionalImageUpload(c)
+	if !ok {
 		return
 	}
 
@@ -72,28 +81,227 @@ func (h *DuckHandler) CreateDuck(c *gin.Context) {
 		OwnerId:        user.UserID,
 		AppearanceJSON: appearanceJSON,
 		ImageData:      fileContent,
+		Position:       position,
 	}
 
 	newDuck, err := h.duckService.CreateDuck(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		var validationErrors validator.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrors):
+			c.Error(validationErrors)
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, imaging.ErrInvalidImage), errors.Is(err, placement.ErrInvalidPosition), errors.Is(err, duckService.ErrInvalidAppearance),
+			errors.Is(err, duckService.ErrImageUploadsDisabled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, cosmeticService.ErrCosmeticLocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, placement.ErrNoFreeSpace):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, duckService.ErrRenderingUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, duck_dto.NewDuckResponse(*newDuck))
+}
+
+// UpdateDuck godoc
+// @Summary      Edit a duck
+// @Description  Changes the name and appearance of a duck owned by the authenticated user, and optionally replaces its image. Without a new image, the image is re-rendered when the appearance changes, and kept as it is when the new appearance cannot be rendered. Cosmetics the duck already wears can be kept after they are retired. The previous values are kept in the duck's edit history.
+// @Tags         ducks
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        duckId      path      int     true   "Duck ID"
+// @Param        name        formData  string  true   "Duck name"
+// @Param        appearance  formData  string  true   "Duck appearance JSON"
+// @Param        image       formData  file    false  "Replacement duck image"
+// @Success      200         {object}  duck_dto.DuckResponse  "Updated duck"
+// @Failure      400         {object}  map[string]string  "Error message"
+// @Failure      403         {object}  map[string]string  "Appearance uses a cosmetic the owner has not unlocked"
+// @Failure      404         {object}  map[string]string  "Duck not found"
+// @Failure      413         {object}  map[string]string  "Image too large"
+// @Failure      500         {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId} [put]
+func (h *DuckHandler) UpdateDuck(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	if !parseDuckForm(c) {
+		return
+	}
+
+	name := c.PostForm("name")
+	appearanceJSON := c.PostForm("appearance")
+
+	if name == "" || appearanceJSON == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and appearance are required"})
+		return
+	}
+
+	fileContent, ok := readOptionalImageUpload(c)
+	if !ok {
+		return
+	}
+
+	duck, err := h.duckService.UpdateDuck(duckService.UpdateDuckRequest{
+		DuckID:         uint(duckId),
+		UserID:         authUser.UserID,
+		Name:           name,
+		AppearanceJSON: appearanceJSON,
+		ImageData:      fileContent,
+	})
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrors):
+			c.Error(validationErrors)
+		case errors.Is(err, duckService.ErrDuckNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, imaging.ErrInvalidImage), errors.Is(err, duckService.ErrInvalidAppearance), errors.Is(err, duckService.ErrImageUploadsDisabled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, cosmeticService.ErrCosmeticLocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, duck_dto.NewDuckResponse(*duck))
+}
+
+// GetDuckEdits godoc
+// @Summary      Get a duck's edit history
+// @Description  Returns every edit made to a duck, newest first
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Param        duckId  path      int  true  "Duck ID"
+// @Success      200     {array}   model.DuckEdit  "Edit history"
+// @Failure      400     {object}  map[string]string  "Invalid duck ID"
+// @Failure      404     {object}  map[string]string  "Duck not found"
+// @Failure      500     {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId}/edits [get]
+func (h *DuckHandler) GetDuckEdits(c *gin.Context) {
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	edits, err := h.duckService.GetDuckEdits(uint(duckId))
+	if err != nil {
+		switch {
+		case errors.Is(err, duckService.ErrDuckNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, newDuck)
+	c.JSON(http.StatusOK, edits)
+}
+
+// maxDuckFormSize caps the body of duck forms: the largest allowed image
+// plus room for the other fields and the multipart framing.
+const maxDuckFormSize = imaging.MaxUploadSize + 1<<20
+
+// parseDuckForm parses a duck form, refusing bodies over maxDuckFormSize
+// with 413 instead of reading them. It reports whether the handler should
+// go on.
+func parseDuckForm(c *gin.Context) bool {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDuckFormSize)
+
+	_, err := c.MultipartForm()
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil, errors.Is(err, http.ErrNotMultipart):
+		return true
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": imaging.ErrImageTooLarge.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form: " + err.Error()})
+	}
+
+	return false
+}
+
+// readOptionalImageUpload reads the "image" upload if there is one,
+// responding with an error itself when it cannot.
+func readOptionalImageUpload(c *gin.Context) ([]byte, bool) {
+	file, err := c.FormFile("image")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, true
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid image upload: " + err.Error()})
+		return nil, false
+	}
+
+	if file.Size > imaging.MaxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": imaging.ErrImageTooLarge.Error()})
+		return nil, false
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open uploaded file: " + err.Error()})
+		return nil, false
+	}
+	defer src.Close()
+
+	fileContent, err := io.ReadAll(io.LimitReader(src, imaging.MaxUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file content: " + err.Error()})
+		return nil, false
+	}
+
+	return fileContent, true
+}
+
+// parsePosition returns nil when neither coordinate is given.
+func parsePosition(rawX, rawY string) (*placement.Point, error) {
+	if rawX == "" && rawY == "" {
+		return nil, nil
+	}
+
+	if rawX == "" || rawY == "" {
+		return nil, errors.New("x and y must be given together")
+	}
+
+	x, errX := strconv.ParseFloat(rawX, 64)
+	y, errY := strconv.ParseFloat(rawY, 64)
+	if errX != nil || errY != nil {
+		return nil, errors.New("x and y must be numbers")
+	}
+
+	return &placement.Point{X: x, Y: y}, nil
 }
 
 // ReactionToDuck godoc
 // @Summary      React to a duck
-// @Description  Add a like or dislike reaction to a duck
+// @Description  Add a reaction to a duck. Reacting again with a different type replaces the previous reaction. The available types are listed by GET /reactions.
 // @Tags         ducks
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        duckId     path      string  true   "Duck ID"
-// @Param        reaction   path      string  true   "Reaction type (like or dislike)"  Enums(like, dislike)
+// @Param        reaction   path      string  true   "Reaction type, e.g. quack"
 // @Success      200        {object}  duck_dto.DuckReactionResponse  "Reaction created"
-// @Failure      400        {object}  map[string]string    "Error message"
+// @Failure      400        {object}  map[string]string    "Invalid duck ID or unknown reaction type"
 // @Failure      404        {object}  map[string]string    "Duck not found"
 // @Failure      409        {object}  map[string]string    "Duck already reacted"
 // @Failure      500        {object}  map[string]string    "Error message"
@@ -104,56 +312,259 @@ func (h *DuckHandler) ReactionToDuck(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
 		return
 	}
-	reaction := model.ReactionType(c.Param("reaction"))
-
 	user, _ := middleware.GetAuthUser(c)
 
-	req := duckService.ReactToDuckRequest{DuckID: uint(duckId), UserID: user.UserID, Reaction: reaction}
+	req := duckService.ReactToDuckRequest{DuckID: uint(duckId), UserID: user.UserID, Reaction: types.ReactionType(c.Param("reaction"))}
 
-	duck, err := h.duckService.ReactionToDuck(req)
+	reaction, err := h.duckService.ReactionToDuck(req)
 	if err != nil {
 		switch {
 		case errors.Is(err, duckService.ErrDuckNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		case errors.Is(err, duckService.ErrDuckAlreadyReacted):
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, duckService.ErrUnknownReaction):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newDuckReactionResponse(*reaction))
+}
+
+// GetReactionTypes godoc
+// @Summary      Get reaction types
+// @Description  Returns the reactions users can leave on ducks, in display order
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}  duck_dto.ReactionTypeResponse  "Reaction types"
+// @Router       /reactions [get]
+func (h *DuckHandler) GetReactionTypes(c *gin.Context) {
+	reactions := h.duckService.ReactionSet()
+
+	response := []duck_dto.ReactionTypeResponse{}
+	for _, reaction := range reactions.Types() {
+		response = append(response, duck_dto.ReactionTypeResponse{
+			Type:     reaction,
+			Negative: reactions.IsNegative(reaction),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDuckReactions godoc
+// @Summary      Get reactions to a duck
+// @Description  Returns a page of the users who reacted to a duck, newest reaction first. Use next_cursor to fetch the following page.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Param        duckId    path      int     true   "Duck ID"
+// @Param        cursor    query     string  false  "Opaque cursor returned as next_cursor by the previous page"
+// @Param        limit     query     int     false  "Page size (default 20, max 100)"
+// @Param        reaction  query     string  false  "Only reactions of this type"
+// @Success      200       {object}  duck_dto.DuckReactionListResponse  "Page of reactions"
+// @Failure      400       {object}  map[string]string  "Error message"
+// @Failure      404       {object}  map[string]string  "Duck not found"
+// @Failure      500       {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId}/reactions [get]
+func (h *DuckHandler) GetDuckReactions(c *gin.Context) {
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	var query duck_dto.DuckReactionListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.duckService.GetDuckReactions(uint(duckId), newReactionListFilter(query))
+	if err != nil {
+		respondReactionListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newDuckReactionListResponse(page))
+}
+
+// GetUserReactions godoc
+// @Summary      Get my reactions
+// @Description  Returns a page of the ducks the authenticated user reacted to, newest reaction first. Use next_cursor to fetch the following page.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor    query     string  false  "Opaque cursor returned as next_cursor by the previous page"
+// @Param        limit     query     int     false  "Page size (default 20, max 100)"
+// @Param        reaction  query     string  false  "Only reactions of this type"
+// @Success      200       {object}  duck_dto.DuckReactionListResponse  "Page of reactions"
+// @Failure      400       {object}  map[string]string  "Error message"
+// @Failure      500       {object}  map[string]string  "Error message"
+// @Router       /user/reactions [get]
+func (h *DuckHandler) GetUserReactions(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+
+	var query duck_dto.DuckReactionListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.duckService.GetUserReactions(authUser.UserID, newReactionListFilter(query))
+	if err != nil {
+		respondReactionListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newDuckReactionListResponse(page))
+}
+
+// RemoveReaction godoc
+// @Summary      Remove a reaction from a duck
+// @Description  Takes back the authenticated user's reaction to a duck. Succeeds even if the user had not reacted.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        duckId   path      int  true  "Duck ID"
+// @Success      200      {object}  duck_dto.DuckResponse  "Duck with updated reaction counts"
+// @Failure      400      {object}  map[string]string  "Invalid duck ID"
+// @Failure      404      {object}  map[string]string  "Duck not found"
+// @Failure      500      {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId}/reaction [delete]
+func (h *DuckHandler) RemoveReaction(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	duck, err := h.duckService.RemoveReaction(authUser.UserID, uint(duckId))
+	if err != nil {
+		switch {
+		case errors.Is(err, duckService.ErrDuckNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, duck)
+	c.JSON(http.StatusOK, duck_dto.NewDuckResponse(*duck))
 }
 
 // GetDucksList godoc
 // @Summary      Get list of ducks
-// @Description  Returns a list of all ducks ordered by creation date
+// @Description  Returns a page of ducks ordered by creation date (newest first). Use next_cursor to fetch the following page.
 // @Tags         ducks
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   duck_dto.DuckResponse  "List of ducks"
+// @Param        cursor          query     string  false  "Opaque cursor returned as next_cursor by the previous page"
+// @Param        limit           query     int     false  "Page size (default 20, max 100)"
+// @Param        skin            query     string  false  "Filter by skin"
+// @Param        accessory       query     string  false  "Filter by accessory"
+// @Param        owner_id        query     int     false  "Filter by owner ID"
+// @Param        created_after   query     string  false  "Only ducks created after this time (RFC3339)"
+// @Param        created_before  query     string  false  "Only ducks created before this time (RFC3339)"
+// @Success      200  {object}  duck_dto.DuckListResponse  "Page of ducks"
+// @Failure      400  {object}  map[string]string  "Error message"
 // @Failure      500  {object}  map[string]string  "Error message"
 // @Router       /ducks [get]
 func (h *DuckHandler) GetDucksList(c *gin.Context) {
-	ducks, err := h.duckService.GetDucksList()
+	var query duck_dto.DuckListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
 
+	page, err := h.duckService.GetDucksList(newDuckListFilter(query))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDuckListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newDuckListResponse(page))
+}
+
+// GetDucksInViewport godoc
+// @Summary      Get ducks inside a viewport
+// @Description  Returns the ducks inside the given region of the party canvas. When the region holds more ducks than the limit, items is empty, truncated is true and density summarizes how many ducks are in each cell of a 16x16 grid over the region.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Param        minX   query     number  true   "Left edge of the viewport"
+// @Param        minY   query     number  true   "Top edge of the viewport"
+// @Param        maxX   query     number  true   "Right edge of the viewport"
+// @Param        maxY   query     number  true   "Bottom edge of the viewport"
+// @Param        limit  query     int     false  "Most ducks to list before summarizing (default 200, max 500)"
+// @Success      200  {object}  duck_dto.DuckViewportResponse  "Ducks in the viewport"
+// @Failure      400  {object}  map[string]string  "Error message"
+// @Failure      500  {object}  map[string]string  "Error message"
+// @Router       /ducks/viewport [get]
+func (h *DuckHandler) GetDucksInViewport(c *gin.Context) {
+	var query duck_dto.DuckViewportQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.duckService.GetDucksInViewport(duckService.Viewport{
+		MinX:  *query.MinX,
+		MinY:  *query.MinY,
+		MaxX:  *query.MaxX,
+		MaxY:  *query.MaxY,
+		Limit: query.Limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, duckService.ErrInvalidViewport):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, ducks)
+	response := duck_dto.DuckViewportResponse{
+		Items:     make([]duck_dto.DuckResponse, len(page.Items)),
+		Total:     page.Total,
+		Truncated: page.Truncated,
+	}
+	for i, duck := range page.Items {
+		response.Items[i] = duck_dto.NewDuckResponse(duck)
+	}
+	for _, cell := range page.Density {
+		response.Density = append(response.Density, duck_dto.DuckDensityCell(cell))
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserDucks godoc
 // @Summary      Get list of ducks for a specific user
-// @Description  Returns a list of all ducks owned by the specified user, ordered by creation date
+// @Description  Returns a page of ducks owned by the specified user, ordered by creation date (newest first)
 // @Tags         ducks
 // @Accept       json
 // @Produce      json
-// @Param        userId   path      int  true  "User ID"
-// @Success      200      {array}   duck_dto.DuckResponse  "List of user's ducks"
+// @Param        userId          path      int     true   "User ID"
+// @Param        cursor          query     string  false  "Opaque cursor returned as next_cursor by the previous page"
+// @Param        limit           query     int     false  "Page size (default 20, max 100)"
+// @Param        skin            query     string  false  "Filter by skin"
+// @Param        accessory       query     string  false  "Filter by accessory"
+// @Param        created_after   query     string  false  "Only ducks created after this time (RFC3339)"
+// @Param        created_before  query     string  false  "Only ducks created before this time (RFC3339)"
+// @Success      200      {object}  duck_dto.DuckListResponse  "Page of user's ducks"
 // @Failure      400      {object}  map[string]string  "Error message"
 // @Failure      500      {object}  map[string]string  "Error message"
 // @Router       /user/{userId}/ducks [get]
@@ -164,48 +575,103 @@ func (h *DuckHandler) GetUserDucks(c *gin.Context) {
 		return
 	}
 
-	ducks, err := h.duckService.GetUserDucksList(uint(userId))
+	var query duck_dto.DuckListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.duckService.GetUserDucksList(uint(userId), newDuckListFilter(query))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondDuckListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newDuckListResponse(page))
+}
+
+// RemoveDuck godoc
+// @Summary      Remove a duck
+// @Description  Moves a duck owned by the authenticated user to their trash. It can be restored for a limited time before it is deleted for good.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        duckId   path      int  true  "Duck ID"
+// @Success      200      {object}  map[string]string  "Success message"
+// @Failure      400      {object}  map[string]string  "Invalid duck ID"
+// @Failure      404      {object}  map[string]string  "Duck not found"
+// @Failure      500      {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId} [delete]
+func (h *DuckHandler) RemoveDuck(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	_, err = h.duckService.RemoveDuck(authUser.UserID, uint(duckId))
+	if err != nil {
+		switch {
+		case errors.Is(err, duckService.ErrDuckNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, ducks)
+	c.JSON(http.StatusOK, gin.H{"message": "Duck removed successfully"})
 }
 
-// GetDucksLeaderboard godoc
-// @Summary      Get ducks leaderboard
-// @Description  Returns the top 100 ducks sorted by rank (highest to lowest)
+// GetUserTrash godoc
+// @Summary      Get removed ducks
+// @Description  Returns the authenticated user's removed ducks that can still be restored, most recently removed first
 // @Tags         ducks
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   duck_dto.DuckResponse  "List of top 100 ducks by rank"
+// @Security     BearerAuth
+// @Success      200  {array}   duck_dto.TrashedDuckResponse  "Removed ducks"
 // @Failure      500  {object}  map[string]string  "Error message"
-// @Router       /leaderboard [get]
-func (h *DuckHandler) GetDucksLeaderboard(c *gin.Context) {
-	ducks, err := h.duckService.GetDucksLeaderboard()
+// @Router       /user/trash [get]
+func (h *DuckHandler) GetUserTrash(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+
+	ducks, err := h.duckService.GetUserTrash(authUser.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, ducks)
+	response := make([]duck_dto.TrashedDuckResponse, len(ducks))
+	for i, duck := range ducks {
+		response[i] = duck_dto.TrashedDuckResponse{
+			Duck:            duck_dto.NewDuckResponse(duck),
+			RestorableUntil: duck.DeletedAt.Time.Add(model.RemovedDuckRetention),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// RemoveDuck godoc
-// @Summary      Remove a duck
-// @Description  Deletes a duck owned by the authenticated user
+// RestoreDuck godoc
+// @Summary      Restore a removed duck
+// @Description  Brings a removed duck back from the authenticated user's trash. Ducks can be restored for a limited time after removal. If its old spot has been taken, the duck is placed somewhere free.
 // @Tags         ducks
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        duckId   path      int  true  "Duck ID"
-// @Success      200      {object}  map[string]string  "Success message"
+// @Success      200      {object}  duck_dto.DuckResponse  "Restored duck"
 // @Failure      400      {object}  map[string]string  "Invalid duck ID"
 // @Failure      404      {object}  map[string]string  "Duck not found"
+// @Failure      409      {object}  map[string]string  "No free space left on the canvas"
+// @Failure      410      {object}  map[string]string  "Duck can no longer be restored"
 // @Failure      500      {object}  map[string]string  "Error message"
-// @Router       /duck/{duckId} [delete]
-func (h *DuckHandler) RemoveDuck(c *gin.Context) {
+// @Router       /duck/{duckId}/restore [post]
+func (h *DuckHandler) RestoreDuck(c *gin.Context) {
 	authUser, _ := middleware.GetAuthUser(c)
 	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
 	if err != nil {
@@ -213,16 +679,154 @@ func (h *DuckHandler) RemoveDuck(c *gin.Context) {
 		return
 	}
 
-	_, err = h.duckService.RemoveDuck(authUser.UserID, uint(duckId))
+	duck, err := h.duckService.RestoreDuck(authUser.UserID, uint(duckId))
 	if err != nil {
 		switch {
 		case errors.Is(err, duckService.ErrDuckNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, duckService.ErrDuckRestoreExpired):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, placement.ErrNoFreeSpace):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Duck removed successfully"})
+	c.JSON(http.StatusOK, duck_dto.NewDuckResponse(*duck))
+}
+
+// MoveDuck godoc
+// @Summary      Move a duck
+// @Description  Moves a duck owned by the authenticated user to a new position on the party canvas. The position must be on the canvas and keep the minimum spacing from other ducks. Moves are rate limited per user and per duck, and the limit is enforced separately by each server instance.
+// @Tags         ducks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        duckId   path      int                   true  "Duck ID"
+// @Param        request  body      duck_dto.MoveDuckDTO  true  "New position"
+// @Success      200      {object}  duck_dto.DuckResponse  "Moved duck"
+// @Failure      400      {object}  map[string]string  "Invalid duck ID or position"
+// @Failure      404      {object}  map[string]string  "Duck not found"
+// @Failure      429      {object}  map[string]string  "Too many moves for this duck"
+// @Failure      500      {object}  map[string]string  "Error message"
+// @Router       /duck/{duckId}/position [patch]
+func (h *DuckHandler) MoveDuck(c *gin.Context) {
+	authUser, _ := middleware.GetAuthUser(c)
+	duckId, err := strconv.ParseUint(c.Param("duckId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duck id"})
+		return
+	}
+
+	var req duck_dto.MoveDuckDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	duck, err := h.duckService.MoveDuck(authUser.UserID, uint(duckId), placement.Point{X: *req.X, Y: *req.Y})
+	if err != nil {
+		switch {
+		case errors.Is(err, duckService.ErrDuckNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, placement.ErrInvalidPosition):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, duck_dto.NewDuckResponse(*duck))
+}
+
+func newDuckListFilter(query duck_dto.DuckListQuery) duckService.DuckListFilter {
+	return duckService.DuckListFilter{
+		Cursor:        query.Cursor,
+		Limit:         query.Limit,
+		Skin:          types.SkinType(query.Skin),
+		Accessory:     types.AccessoryType(query.Accessory),
+		OwnerID:       query.OwnerID,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+	}
+}
+
+func newDuckListResponse(page *duckService.DuckListPage) duck_dto.DuckListResponse {
+	items := make([]duck_dto.DuckResponse, len(page.Items))
+	for i, duck := range page.Items {
+		items[i] = duck_dto.NewDuckResponse(duck)
+	}
+
+	return duck_dto.DuckListResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+}
+
+func respondDuckListError(c *gin.Context, err error) {
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func newReactionListFilter(query duck_dto.DuckReactionListQuery) duckService.ReactionListFilter {
+	return duckService.ReactionListFilter{
+		Cursor:   query.Cursor,
+		Limit:    query.Limit,
+		Reaction: types.ReactionType(query.Reaction),
+	}
+}
+
+func newDuckReactionListResponse(page *duckService.ReactionListPage) duck_dto.DuckReactionListResponse {
+	items := make([]duck_dto.DuckReactionResponse, len(page.Items))
+	for i, reaction := range page.Items {
+		items[i] = newDuckReactionResponse(reaction)
+	}
+
+	return duck_dto.DuckReactionListResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+}
+
+// newDuckReactionResponse only includes the user and duck when they were
+// loaded with the reaction.
+func newDuckReactionResponse(reaction model.DuckReactions) duck_dto.DuckReactionResponse {
+	response := duck_dto.DuckReactionResponse{
+		UserID:    reaction.UserID,
+		DuckID:    reaction.DuckID,
+		Reaction:  reaction.Reaction,
+		CreatedAt: reaction.CreatedAt,
+	}
+
+	if reaction.User.ID != 0 {
+		user := duck_dto.NewDuckUserResponse(reaction.User)
+		response.User = &user
+	}
+
+	if reaction.Duck.ID != 0 {
+		duck := duck_dto.NewDuckResponse(reaction.Duck)
+		response.Duck = &duck
+	}
+
+	return response
+}
+
+func respondReactionListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, duckService.ErrDuckNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, duckService.ErrUnknownReaction), errors.Is(err, utils.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	duck_dto "github.com/omidnikrah/duckparty-backend/internal/dto/duck"
+	leaderboard_dto "github.com/omidnikrah/duckparty-backend/internal/dto/leaderboard"
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	leaderboardService "github.com/omidnikrah/duckparty-backend/internal/service/leaderboard"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *leaderboardService.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *leaderboardService.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetLeaderboard godoc
+// @Summary      Get ducks leaderboard
+// @Description  Returns the top ducks of the current day, week (starting Monday) or month in UTC, ranked by the reactions they received in that period, or the all-time top ducks. Day, week and month rankings are refreshed at most every 30 seconds. Positive reactions rank a duck higher; negative ones break ties. Without period, the response keeps its original format: a plain array of the all-time top ducks (DuckResponse), sorted by rank. With period, including period=all, it is a LeaderboardResponse.
+// @Tags         leaderboard
+// @Accept       json
+// @Produce      json
+// @Param        period  query     string  false  "Leaderboard period; omit for the original all-time array"  Enums(day, week, month, all)
+// @Param        limit   query     int     false  "Number of ducks (default and max 100)"
+// @Success      200     {object}  leaderboard_dto.LeaderboardResponse  "Leaderboard, or an array of DuckResponse without period"
+// @Failure      400     {object}  map[string]string  "Error message"
+// @Failure      500     {object}  map[string]string  "Error message"
+// @Router       /leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	var query leaderboard_dto.LeaderboardQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	period := model.LeaderboardPeriod(query.Period)
+	if period == "" {
+		period = model.PeriodAll
+	}
+
+	leaderboard, err := h.leaderboardService.GetLeaderboard(period, query.Limit)
+	if err != nil {
+		respondLeaderboardError(c, err)
+		return
+	}
+
+	if query.Period == "" {
+		ducks := make([]duck_dto.DuckResponse, 0, len(leaderboard.Entries))
+		for _, entry := range leaderboard.Entries {
+			ducks = append(ducks, duck_dto.NewDuckResponse(*entry.Duck))
+		}
+
+		c.JSON(http.StatusOK, ducks)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLeaderboardResponse(leaderboard))
+}
+
+// GetLeaderboardHistory godoc
+// @Summary      Get past leaderboard winners
+// @Description  Returns the top ducks of a finished day, week or month, as saved when the period ended. Without at, the last finished period is returned.
+// @Tags         leaderboard
+// @Accept       json
+// @Produce      json
+// @Param        period  query     string  true   "Leaderboard period"  Enums(day, week, month)
+// @Param        at      query     string  false  "Any time within the period (RFC3339)"
+// @Param        limit   query     int     false  "Number of ducks (default and max 100)"
+// @Success      200     {object}  leaderboard_dto.LeaderboardResponse  "Leaderboard snapshot"
+// @Failure      400     {object}  map[string]string  "Error message"
+// @Failure      404     {object}  map[string]string  "No snapshot for this period"
+// @Failure      500     {object}  map[string]string  "Error message"
+// @Router       /leaderboard/history [get]
+func (h *LeaderboardHandler) GetLeaderboardHistory(c *gin.Context) {
+	var query leaderboard_dto.LeaderboardHistoryQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(err)
+		return
+	}
+
+	leaderboard, err := h.leaderboardService.GetSnapshot(model.LeaderboardPeriod(query.Period), query.At, query.Limit)
+	if err != nil {
+		respondLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLeaderboardResponse(leaderboard))
+}
+
+func newLeaderboardResponse(leaderboard *leaderboardService.Leaderboard) leaderboard_dto.LeaderboardResponse {
+	entries := make([]leaderboard_dto.LeaderboardEntry, len(leaderboard.Entries))
+	for i, entry := range leaderboard.Entries {
+		counts := make(map[string]int64, len(entry.ReactionCounts))
+		for reaction, count := range entry.ReactionCounts {
+			counts[string(reaction)] = count
+		}
+
+		entries[i] = leaderboard_dto.LeaderboardEntry{
+			Rank:           entry.Rank,
+			ReactionCounts: counts,
+			DuckName:       entry.DuckName,
+			DuckImage:      entry.DuckImage,
+		}
+
+		if entry.Duck != nil {
+			duck := duck_dto.NewDuckResponse(*entry.Duck)
+			entries[i].Duck = &duck
+		}
+	}
+
+	return leaderboard_dto.LeaderboardResponse{
+		Period:   string(leaderboard.Period),
+		StartsAt: leaderboard.StartsAt,
+		EndsAt:   leaderboard.EndsAt,
+		Entries:  entries,
+	}
+}
+
+func respondLeaderboardError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, leaderboardService.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, leaderboardService.ErrSnapshotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -6,15 +6,17 @@ import (
 	"github.com/gin-gonic/gin"
 	user_dto "github.com/omidnikrah/duckparty-backend/internal/dto/user"
 	"github.com/omidnikrah/duckparty-backend/internal/middleware"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
 	userService "github.com/omidnikrah/duckparty-backend/internal/service/user"
 )
 
 type UserHandler struct {
-	userService *userService.UserService
+	userService     *userService.UserService
+	cosmeticService *cosmeticService.CosmeticService
 }
 
-func NewUserHandler(userService *userService.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService *userService.UserService, cosmeticService *cosmeticService.CosmeticService) *UserHandler {
+	return &UserHandler{userService: userService, cosmeticService: cosmeticService}
 }
 
 // Authenticate godoc
@@ -202,7 +204,7 @@ func (h *UserHandler) CreateAnonymousUser(c *gin.Context) {
 
 // GetMeUser godoc
 // @Summary      Get current user information
-// @Description  Returns the information of the currently authenticated user
+// @Description  Returns the information of the currently authenticated user, including the cosmetics they can use
 // @Tags         user
 // @Accept       json
 // @Produce      json
@@ -219,7 +221,14 @@ func (h *UserHandler) GetMeUser(c *gin.Context) {
 		return
 	}
 
+	inventory, err := h.cosmeticService.GetInventory(authUser.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"user": meUser,
+		"user":      meUser,
+		"inventory": inventory,
 	})
 }
@@ -3,44 +3,115 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/omidnikrah/duckparty-backend/internal/config"
+	"github.com/omidnikrah/duckparty-backend/internal/middleware"
 	ws "github.com/omidnikrah/duckparty-backend/internal/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
-
 type WebSocketHandler struct {
 	broadcaster *ws.SocketBroadcaster
+	config      *config.Config
+	upgrader    websocket.Upgrader
 }
 
-func NewWebSocketHandler(broadcaster *ws.SocketBroadcaster) *WebSocketHandler {
+func NewWebSocketHandler(broadcaster *ws.SocketBroadcaster, config *config.Config) *WebSocketHandler {
 	return &WebSocketHandler{
 		broadcaster: broadcaster,
+		config:      config,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: newOriginChecker(config.WSAllowedOrigins),
+		},
+	}
+}
+
+// newOriginChecker allows any origin when the allowlist is empty or contains
+// "*"; otherwise the Origin header must match an entry exactly. Requests
+// without an Origin header come from non-browser clients and are allowed.
+func newOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			return func(r *http.Request) bool { return true }
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = struct{}{}
+	}
+
+	if len(allowed) == 0 {
+		return func(r *http.Request) bool { return true }
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		_, ok := allowed[origin]
+		return ok
 	}
 }
 
 // HandleWebSocket handles websocket requests from clients
 // @Summary      WebSocket connection for real-time duck notifications
-// @Description  Establishes a WebSocket connection to receive real-time notifications when new ducks are added
+// @Description  Establishes a WebSocket connection to receive real-time notifications: new_duck_created, duck_reaction_changed, duck_removed, duck_restored, duck_updated, duck_renamed, duck_moved and leaderboard_updated.
+// @Description  Connections start subscribed to the "ducks" topic. Send {"action":"subscribe","topics":[...]} or {"action":"unsubscribe","topics":[...]} to change subscriptions; available topics are "ducks", "leaderboard", "duck:{id}" and "user:{id}".
+// @Description  Authenticate with a JWT either through the token query parameter or by sending {"action":"auth","token":"..."} as a message. Authenticated connections are subscribed to their own "user:{id}" topic, which carries duck_reaction_received notifications; other users' topics cannot be subscribed to.
+// @Description  Every broadcast notification carries an increasing "id". After reconnecting, send {"action":"resume","last_id":N} to receive the missed notifications for the current subscriptions followed by a "resumed" message, or a "resync_required" message when they are no longer retained and the client has to reload.
 // @Tags         websocket
 // @Accept       json
 // @Produce      json
+// @Param        token  query  string  false  "JWT used to authenticate the connection"
 // @Success      101  "Switching Protocols"
 // @Failure      400  {object}  map[string]string  "Error message"
+// @Failure      401  {object}  map[string]string  "Invalid token"
+// @Failure      403  {object}  map[string]string  "Origin not allowed"
+// @Failure      429  {object}  map[string]string  "Too many connections for this user"
 // @Router       /ws [get]
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if !h.upgrader.CheckOrigin(c.Request) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Origin not allowed"})
+		return
+	}
+
+	var userID uint
+	if token := c.Query("token"); token != "" {
+		authUser, err := middleware.ParseToken(h.config, token)
+		if err != nil || authUser.UserID == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		userID = authUser.UserID
+	}
+
+	if !h.broadcaster.CanAccept(userID) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": ws.ErrTooManyConnections.Error()})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		slog.Error("Failed to upgrade WebSocket connection", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to upgrade connection"})
 		return
 	}
 
-	h.broadcaster.Add(conn)
+	if err := h.broadcaster.Add(conn, userID); err != nil {
+		slog.Warn("Rejected WebSocket connection", "user_id", userID, "error", err)
+	}
+}
+
+// GetStats godoc
+// @Summary      Get WebSocket stats
+// @Description  Returns the number of open WebSocket connections and slow-consumer evictions on the instance serving the request. Each replica keeps its own stats.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminKey
+// @Success      200  {object}  ws.Stats           "WebSocket stats"
+// @Failure      403  {object}  map[string]string  "Forbidden"
+// @Router       /admin/websocket/stats [get]
+func (h *WebSocketHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.broadcaster.Stats())
 }
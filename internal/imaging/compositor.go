@@ -0,0 +1,157 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"golang.org/x/image/draw"
+)
+
+var ErrMissingSprite = errors.New("missing sprite")
+
+// Sprite layout inside the sprites directory. Every sprite is drawn over the
+// full canvas, so they should share the base sprite's size.
+const (
+	baseSpriteName      = "base.png"
+	skinSpritesDir      = "skins"
+	accessorySpritesDir = "accessories"
+)
+
+// Layers are drawn from the lowest z-index up: back accessories sit behind
+// the body, the skin covers the base and head accessories go on top.
+const (
+	zBack = iota
+	zBase
+	zSkin
+	zHead
+)
+
+var slotZIndex = map[types.CosmeticSlot]int{
+	types.SlotBack: zBack,
+	types.SlotHead: zHead,
+}
+
+// Compositor renders duck images from their appearance by layering sprite
+// assets. Decoded sprites are cached for the life of the process.
+type Compositor struct {
+	dir string
+
+	mu      sync.RWMutex
+	sprites map[string]image.Image
+}
+
+func NewCompositor(spritesDir string) *Compositor {
+	return &Compositor{
+		dir:     spritesDir,
+		sprites: make(map[string]image.Image),
+	}
+}
+
+type layer struct {
+	z    int
+	path string
+}
+
+// Render draws appearance using catalog to place each accessory in its
+// slot's layer.
+func (c *Compositor) Render(appearance types.DuckAppearance, catalog types.AppearanceCatalog) (image.Image, error) {
+	layers := []layer{
+		{z: zBase, path: baseSpriteName},
+		{z: zSkin, path: spritePath(skinSpritesDir, string(appearance.Skin))},
+	}
+
+	for _, accessory := range appearance.Accessories {
+		z := zHead
+		if slot, ok := catalog.AccessorySlot(accessory); ok {
+			if slotZ, ok := slotZIndex[slot]; ok {
+				z = slotZ
+			}
+		}
+		layers = append(layers, layer{z: z, path: spritePath(accessorySpritesDir, string(accessory))})
+	}
+
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].z < layers[j].z })
+
+	base, err := c.sprite(baseSpriteName)
+	if err != nil {
+		return nil, err
+	}
+
+	canvas := image.NewNRGBA(base.Bounds().Sub(base.Bounds().Min))
+	for _, l := range layers {
+		sprite, err := c.sprite(l.path)
+		if err != nil {
+			return nil, err
+		}
+
+		draw.CatmullRom.Scale(canvas, canvas.Bounds(), sprite, sprite.Bounds(), draw.Over, nil)
+	}
+
+	return canvas, nil
+}
+
+// Check loads the base sprite and the sprite of every cosmetic, so missing
+// or broken assets are found at startup rather than on the first render.
+// cosmetics maps cosmetic IDs to their slots.
+func (c *Compositor) Check(cosmetics map[string]types.CosmeticSlot) error {
+	names := []string{baseSpriteName}
+	for id, slot := range cosmetics {
+		dir := accessorySpritesDir
+		if slot == types.SlotSkin {
+			dir = skinSpritesDir
+		}
+		names = append(names, spritePath(dir, id))
+	}
+	sort.Strings(names[1:])
+
+	var errs []error
+	for _, name := range names {
+		if _, err := c.sprite(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// spritePath maps a cosmetic ID to its sprite file. Base keeps IDs from
+// escaping the sprites directory.
+func spritePath(dir string, id string) string {
+	return filepath.Join(dir, filepath.Base(id)+".png")
+}
+
+func (c *Compositor) sprite(name string) (image.Image, error) {
+	c.mu.RLock()
+	sprite, ok := c.sprites[name]
+	c.mu.RUnlock()
+	if ok {
+		return sprite, nil
+	}
+
+	file, err := os.Open(filepath.Join(c.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrMissingSprite, name)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	sprite, err = png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sprite %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	c.sprites[name] = sprite
+	c.mu.Unlock()
+
+	return sprite, nil
+}
@@ -0,0 +1,155 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	MaxUploadSize = 5 << 20 // 5 MiB
+	MaxDimension  = 2048
+	MinDimension  = 32
+	MediumSize    = 512
+	ThumbnailSize = 128
+)
+
+const ContentTypePNG = "image/png"
+
+var (
+	ErrInvalidImage       = errors.New("invalid image")
+	ErrUnsupportedFormat  = fmt.Errorf("%w: only png, jpeg, gif and webp are supported", ErrInvalidImage)
+	ErrImageTooLarge      = fmt.Errorf("%w: file must be at most %d bytes", ErrInvalidImage, MaxUploadSize)
+	ErrDimensionsTooLarge = fmt.Errorf("%w: width and height must be at most %dpx", ErrInvalidImage, MaxDimension)
+	ErrDimensionsTooSmall = fmt.Errorf("%w: width and height must be at least %dpx", ErrInvalidImage, MinDimension)
+)
+
+type decodeFunc func([]byte) (image.Image, error)
+
+var decoders = map[string]decodeFunc{
+	"image/png":  decodeWith(png.DecodeConfig, png.Decode),
+	"image/jpeg": decodeWith(jpeg.DecodeConfig, jpeg.Decode),
+	"image/gif":  decodeWith(gif.DecodeConfig, gif.Decode),
+	"image/webp": decodeWith(webp.DecodeConfig, webp.Decode),
+}
+
+// ProcessedImage holds PNG encodings of an upload at every size we store.
+type ProcessedImage struct {
+	Original  []byte
+	Medium    []byte
+	Thumbnail []byte
+}
+
+// Process sniffs, validates and re-encodes an uploaded image. Re-encoding to
+// PNG drops any metadata (EXIF, ICC, comments) carried by the source file.
+func Process(data []byte) (*ProcessedImage, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrImageTooLarge
+	}
+
+	decode, ok := decoders[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	src, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ProcessImage(src)
+}
+
+// ProcessImage encodes an already decoded image at every size we store.
+func ProcessImage(src image.Image) (*ProcessedImage, error) {
+	original, err := encodePNG(fit(src, MaxDimension))
+	if err != nil {
+		return nil, err
+	}
+
+	medium, err := encodePNG(fit(src, MediumSize))
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, err := encodePNG(fit(src, ThumbnailSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProcessedImage{
+		Original:  original,
+		Medium:    medium,
+		Thumbnail: thumbnail,
+	}, nil
+}
+
+func decodeWith(
+	decodeConfig func(r io.Reader) (image.Config, error),
+	decodeImage func(r io.Reader) (image.Image, error),
+) decodeFunc {
+	return func(data []byte) (image.Image, error) {
+		cfg, err := decodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+
+		// Check dimensions before decoding so a tiny file claiming a huge
+		// canvas never gets its pixels allocated.
+		if cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+			return nil, ErrDimensionsTooLarge
+		}
+		if cfg.Width < MinDimension || cfg.Height < MinDimension {
+			return nil, ErrDimensionsTooSmall
+		}
+
+		img, err := decodeImage(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+
+		return img, nil
+	}
+}
+
+// fit scales src down to fit inside a size x size box, keeping its aspect
+// ratio. Images that already fit are copied as-is.
+func fit(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > size || height > size {
+		if width >= height {
+			height = max(1, height*size/width)
+			width = size
+		} else {
+			width = max(1, width*size/height)
+			height = size
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omidnikrah/duckparty-backend/internal/config"
+)
+
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminMiddleware guards admin routes with a shared key. Without a
+// configured key every admin request is refused.
+func AdminMiddleware(config *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(AdminKeyHeader)
+		if config.AdminAPIKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(config.AdminAPIKey)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			c.Abort()
+			return
+		}
+	}
+}
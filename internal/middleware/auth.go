@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,6 +18,39 @@ type AuthUser struct {
 
 const AuthUserKey = "user"
 
+var ErrInvalidToken = errors.New("invalid token")
+
+// ParseToken validates a JWT issued by the user service and returns the user
+// it was issued for.
+func ParseToken(config *config.Config, tokenValue string) (AuthUser, error) {
+	tokenValue = strings.TrimPrefix(tokenValue, "Bearer ")
+	if tokenValue == "" {
+		return AuthUser{}, ErrInvalidToken
+	}
+
+	token, err := jwt.Parse(tokenValue, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.JWTSecret), nil
+	})
+	if err != nil {
+		return AuthUser{}, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return AuthUser{}, ErrInvalidToken
+	}
+
+	email, _ := claims["email"].(string)
+	subString, _ := claims["sub"].(string)
+
+	sub, _ := strconv.ParseUint(subString, 10, 64)
+
+	return AuthUser{
+		Email:  email,
+		UserID: uint(sub),
+	}, nil
+}
+
 func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenValue := c.GetHeader("Authorization")
@@ -26,38 +60,14 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		tokenValue = strings.TrimPrefix(tokenValue, "Bearer ")
-		if tokenValue == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
-			return
-		}
-
-		token, err := jwt.Parse(tokenValue, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.JWTSecret), nil
-		})
+		authUser, err := ParseToken(config, tokenValue)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			email, _ := claims["email"].(string)
-			subString, _ := claims["sub"].(string)
-
-			sub, _ := strconv.ParseUint(subString, 10, 64)
-
-			c.Set(AuthUserKey, AuthUser{
-				Email:  email,
-				UserID: uint(sub),
-			})
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-
-			c.Abort()
-			return
-		}
+		c.Set(AuthUserKey, authUser)
 	}
 }
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,19 +10,38 @@ import (
 	"github.com/ulule/limiter/v3/drivers/store/memory"
 )
 
+// rateLimitStore keeps counters in memory, so every limit is enforced per
+// server instance rather than across replicas.
 var rateLimitStore = memory.NewStore()
 
 var (
 	AuthRateLimit   = limiter.Rate{Period: 1 * time.Minute, Limit: 10}
 	CreateRateLimit = limiter.Rate{Period: 1 * time.Minute, Limit: 10}
+	MoveRateLimit   = limiter.Rate{Period: 1 * time.Minute, Limit: 20}
 )
 
 func RateLimit(rate limiter.Rate) gin.HandlerFunc {
 	return ginlimiter.NewMiddleware(
 		limiter.New(rateLimitStore, rate),
-		ginlimiter.WithLimitReachedHandler(func(c *gin.Context) {
-			c.JSON(429, gin.H{"error": "Too many requests"})
-			c.Abort()
+		ginlimiter.WithLimitReachedHandler(limitReached),
+	)
+}
+
+// RateLimitByUserParam limits requests per authenticated user and value of
+// a route parameter, e.g. per duck rather than per client, without letting
+// other users spend the owner's budget. It must run after AuthMiddleware.
+func RateLimitByUserParam(rate limiter.Rate, param string) gin.HandlerFunc {
+	return ginlimiter.NewMiddleware(
+		limiter.New(rateLimitStore, rate),
+		ginlimiter.WithKeyGetter(func(c *gin.Context) string {
+			authUser, _ := GetAuthUser(c)
+			return fmt.Sprintf("%s:%d:%s", c.FullPath(), authUser.UserID, c.Param(param))
 		}),
+		ginlimiter.WithLimitReachedHandler(limitReached),
 	)
 }
+
+func limitReached(c *gin.Context) {
+	c.JSON(429, gin.H{"error": "Too many requests"})
+	c.Abort()
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+)
+
+type CosmeticRarity string // @name CosmeticRarity
+
+const (
+	RarityCommon    CosmeticRarity = "common"
+	RarityRare      CosmeticRarity = "rare"
+	RarityEpic      CosmeticRarity = "epic"
+	RarityLegendary CosmeticRarity = "legendary"
+)
+
+// CosmeticUnlockRule decides who may use a cosmetic. Free cosmetics are
+// available to everyone; the others have to be unlocked into a user's
+// inventory first.
+type CosmeticUnlockRule string // @name CosmeticUnlockRule
+
+const (
+	UnlockFree CosmeticUnlockRule = "free"
+	// UnlockFirstDuck is granted once the user has created a duck.
+	UnlockFirstDuck CosmeticUnlockRule = "first_duck"
+	// UnlockTopLeaderboard is granted when one of the user's ducks reaches
+	// rank UnlockThreshold or better.
+	UnlockTopLeaderboard CosmeticUnlockRule = "top_leaderboard"
+	// UnlockLikesReceived is granted once the user's ducks have received
+	// UnlockThreshold likes in total.
+	UnlockLikesReceived CosmeticUnlockRule = "likes_received"
+)
+
+// Cosmetic is a skin or accessory ducks can wear. It can only be used while
+// inside its availability window; a nil bound leaves that side open.
+type Cosmetic struct {
+	ID              string             `json:"id" gorm:"primaryKey"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	Slot            types.CosmeticSlot `json:"slot" gorm:"type:text;not null;index"`
+	DisplayName     string             `json:"display_name" gorm:"not null"`
+	AssetURL        string             `json:"asset_url" gorm:"not null;default:''"`
+	Rarity          CosmeticRarity     `json:"rarity" gorm:"type:text;not null;default:'common'"`
+	AvailableFrom   *time.Time         `json:"available_from,omitempty"`
+	AvailableUntil  *time.Time         `json:"available_until,omitempty"`
+	UnlockRule      CosmeticUnlockRule `json:"unlock_rule" gorm:"type:text;not null;default:'free';index"`
+	UnlockThreshold int                `json:"unlock_threshold,omitempty" gorm:"not null;default:0"`
+}
@@ -7,19 +7,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// RemovedDuckRetention is how long a removed duck stays in its owner's trash
+// and can be restored before it is deleted for good.
+const RemovedDuckRetention = 7 * 24 * time.Hour
+
+// LeaderboardSize is the number of ranked ducks the leaderboard shows.
+const LeaderboardSize = 100
+
 type Duck struct {
-	ID            uint                 `json:"id" gorm:"primarykey"`
-	CreatedAt     time.Time            `json:"created_at"`
-	UpdatedAt     time.Time            `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt       `json:"deleted_at,omitempty" gorm:"index"`
-	OwnerID       uint                 `json:"owner_id" gorm:"not null;index"`
-	Owner         User                 `json:"owner" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Name          string               `json:"name" gorm:"not null"`
-	X             float64              `json:"x" gorm:"not null"`
-	Y             float64              `json:"y" gorm:"not null"`
-	Appearance    types.DuckAppearance `json:"appearance" gorm:"serializer:json;type:jsonb;not null"`
-	Image         string               `json:"image" gorm:"not null"`
-	LikesCount    int64                `json:"likes_count" gorm:"not null;default:0"`
-	DislikesCount int64                `json:"dislikes_count" gorm:"not null;default:0"`
-	Rank          uint                 `json:"rank" gorm:"not null;default:0"`
+	ID             uint                 `json:"id" gorm:"primarykey;index:idx_duck_created_at_id,priority:2"`
+	CreatedAt      time.Time            `json:"created_at" gorm:"index:idx_duck_created_at_id,priority:1"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt       `json:"deleted_at,omitempty" gorm:"index"`
+	OwnerID        uint                 `json:"owner_id" gorm:"not null;index"`
+	Owner          User                 `json:"owner" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Name           string               `json:"name" gorm:"not null"`
+	X              float64              `json:"x" gorm:"not null;index:idx_duck_position,priority:1"`
+	Y              float64              `json:"y" gorm:"not null;index:idx_duck_position,priority:2"`
+	Appearance     types.DuckAppearance `json:"appearance" gorm:"serializer:json;type:jsonb;not null"`
+	Image          string               `json:"image" gorm:"not null"`
+	ImageMedium    string               `json:"image_medium" gorm:"not null;default:''"`
+	ImageThumbnail string               `json:"image_thumbnail" gorm:"not null;default:''"`
+	ReactionCounts ReactionCounts       `json:"reaction_counts" gorm:"type:jsonb;not null;default:'{}'"`
+	Rank           uint                 `json:"rank" gorm:"not null;default:0"`
+	ImagesPurgedAt *time.Time           `json:"-" gorm:"index"`
+}
+
+// ImageURLs returns every stored image variant of the duck.
+func (d *Duck) ImageURLs() []string {
+	urls := make([]string, 0, 3)
+	for _, url := range []string{d.Image, d.ImageMedium, d.ImageThumbnail} {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
 }
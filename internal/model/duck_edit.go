@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+)
+
+// DuckEdit records one owner edit of a duck, keeping the values it replaced.
+type DuckEdit struct {
+	ID                 uint                 `json:"id" gorm:"primarykey"`
+	CreatedAt          time.Time            `json:"created_at"`
+	DuckID             uint                 `json:"duck_id" gorm:"not null;index"`
+	Duck               Duck                 `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	EditorID           uint                 `json:"editor_id" gorm:"not null"`
+	PreviousName       string               `json:"previous_name" gorm:"not null"`
+	Name               string               `json:"name" gorm:"not null"`
+	PreviousAppearance types.DuckAppearance `json:"previous_appearance" gorm:"serializer:json;type:jsonb;not null"`
+	Appearance         types.DuckAppearance `json:"appearance" gorm:"serializer:json;type:jsonb;not null"`
+	PreviousImage      string               `json:"previous_image" gorm:"not null;default:''"`
+	Image              string               `json:"image" gorm:"not null;default:''"`
+}
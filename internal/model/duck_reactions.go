@@ -1,19 +1,74 @@
 package model
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
 
-type ReactionType string // @name ReactionType
-
-const (
-	ReactionLike    ReactionType = "like"
-	ReactionDislike ReactionType = "dislike"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
 )
 
 type DuckReactions struct {
-	UserID    uint         `json:"user_id" gorm:"not null;primaryKey;uniqueIndex:idx_duck_user"`
-	DuckID    uint         `json:"duck_id" gorm:"not null;primaryKey;uniqueIndex:idx_duck_user"`
-	Reaction  ReactionType `json:"reaction" gorm:"type:text;not null;default:'like'"`
-	User      User         `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Duck      Duck         `json:"duck" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	CreatedAt time.Time    `gorm:"not null;default:now()"`
+	UserID    uint               `json:"user_id" gorm:"not null;primaryKey;uniqueIndex:idx_duck_user;index:idx_duck_reactions_user_created,priority:1"`
+	DuckID    uint               `json:"duck_id" gorm:"not null;primaryKey;uniqueIndex:idx_duck_user;index:idx_duck_reactions_duck_created,priority:1"`
+	Reaction  types.ReactionType `json:"reaction" gorm:"type:text;not null;default:'like'"`
+	User      User               `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Duck      Duck               `json:"duck" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt time.Time          `gorm:"not null;default:now();index:idx_duck_reactions_user_created,priority:2;index:idx_duck_reactions_duck_created,priority:2;index:idx_duck_reactions_created_at"`
+}
+
+// ReactionCounts is how many reactions of each type a duck received.
+// Reactions nobody left are omitted.
+type ReactionCounts map[types.ReactionType]int64 // @name ReactionCounts
+
+// Add changes the count of reaction by delta, never going below zero.
+func (c ReactionCounts) Add(reaction types.ReactionType, delta int64) {
+	count := c[reaction] + delta
+	if count <= 0 {
+		delete(c, reaction)
+		return
+	}
+
+	c[reaction] = count
+}
+
+// Value stores the counts as a jsonb object, never as JSON null, so SQL can
+// always treat the column as an object.
+func (c ReactionCounts) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(map[types.ReactionType]int64(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (c *ReactionCounts) Scan(value interface{}) error {
+	counts := ReactionCounts{}
+
+	switch data := value.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(data, &counts); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(data), &counts); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported reaction counts type %T", value)
+	}
+
+	if counts == nil {
+		counts = ReactionCounts{}
+	}
+	*c = counts
+
+	return nil
 }
@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// LeaderboardPeriod is the window a leaderboard ranks reactions over.
+// Periods are calendar days, ISO weeks and months in UTC.
+type LeaderboardPeriod string // @name LeaderboardPeriod
+
+const (
+	PeriodDay   LeaderboardPeriod = "day"
+	PeriodWeek  LeaderboardPeriod = "week"
+	PeriodMonth LeaderboardPeriod = "month"
+	PeriodAll   LeaderboardPeriod = "all"
+)
+
+// SnapshotPeriods are the periods whose winners are kept once they end.
+var SnapshotPeriods = []LeaderboardPeriod{PeriodDay, PeriodWeek, PeriodMonth}
+
+func (p LeaderboardPeriod) IsValid() bool {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodAll:
+		return true
+	}
+
+	return false
+}
+
+// Bounds returns the start and end of the period containing t. ok is false
+// for PeriodAll, which has no bounds.
+func (p LeaderboardPeriod) Bounds(t time.Time) (start time.Time, end time.Time, ok bool) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch p {
+	case PeriodDay:
+		return day, day.AddDate(0, 0, 1), true
+	case PeriodWeek:
+		// Weeks start on Monday.
+		start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return start, start.AddDate(0, 0, 7), true
+	case PeriodMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), true
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// Previous returns the bounds of the period that ended right before the one
+// containing t.
+func (p LeaderboardPeriod) Previous(t time.Time) (start time.Time, end time.Time, ok bool) {
+	current, _, ok := p.Bounds(t)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return p.Bounds(current.Add(-time.Nanosecond))
+}
+
+// LeaderboardSnapshotEntry is one ranked duck of a finished leaderboard
+// period, with the reactions it received during that period.
+type LeaderboardSnapshotEntry struct {
+	ID        uint              `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time         `json:"created_at"`
+	Period    LeaderboardPeriod `json:"period" gorm:"type:text;not null;uniqueIndex:idx_leaderboard_snapshot_rank,priority:1"`
+	StartsAt  time.Time         `json:"starts_at" gorm:"not null;uniqueIndex:idx_leaderboard_snapshot_rank,priority:2"`
+	EndsAt    time.Time         `json:"ends_at" gorm:"not null"`
+	Rank      uint              `json:"rank" gorm:"not null;uniqueIndex:idx_leaderboard_snapshot_rank,priority:3"`
+	// DuckID is cleared once the duck is deleted for good; DuckName and
+	// DuckImage keep the entry showing it.
+	DuckID         *uint          `json:"duck_id" gorm:"index"`
+	Duck           *Duck          `json:"duck,omitempty" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	DuckName       string         `json:"duck_name" gorm:"not null;default:''"`
+	DuckImage      string         `json:"duck_image" gorm:"not null;default:''"`
+	ReactionCounts ReactionCounts `json:"reaction_counts" gorm:"type:jsonb;not null;default:'{}'"`
+}
+
+// LeaderboardSnapshotPeriod marks a finished period as saved, including
+// periods without any reactions, which have no entries.
+type LeaderboardSnapshotPeriod struct {
+	Period    LeaderboardPeriod `json:"period" gorm:"type:text;primaryKey"`
+	StartsAt  time.Time         `json:"starts_at" gorm:"primaryKey"`
+	EndsAt    time.Time         `json:"ends_at" gorm:"not null"`
+	CreatedAt time.Time         `json:"created_at"`
+}
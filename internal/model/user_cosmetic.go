@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// UserCosmetic is a cosmetic unlocked into a user's inventory.
+type UserCosmetic struct {
+	UserID     uint      `json:"user_id" gorm:"primaryKey"`
+	CosmeticID string    `json:"cosmetic_id" gorm:"primaryKey"`
+	UnlockedAt time.Time `json:"unlocked_at" gorm:"not null;default:now()"`
+	User       User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Cosmetic   Cosmetic  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
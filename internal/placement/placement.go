@@ -0,0 +1,191 @@
+package placement
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+const (
+	CanvasWidth  = 8000.0
+	CanvasHeight = 8000.0
+	// MinSpacing is the smallest allowed distance between two ducks.
+	MinSpacing = 80.0
+	// TileSize is the side of the square tiles the canvas is split into for
+	// placement, so a placement only needs to look at the ducks near one
+	// tile.
+	TileSize = 10 * MinSpacing
+
+	randomAttempts = 200
+)
+
+var (
+	ErrInvalidPosition = errors.New("invalid position")
+	ErrOutOfBounds     = fmt.Errorf("%w: position must be within %.0fx%.0f", ErrInvalidPosition, CanvasWidth, CanvasHeight)
+	ErrTooClose        = fmt.Errorf("%w: position is closer than %.0f to another duck", ErrInvalidPosition, MinSpacing)
+	ErrNoFreeSpace     = errors.New("no free space left on the canvas")
+)
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func (p Point) distanceSquared(other Point) float64 {
+	dx, dy := p.X-other.X, p.Y-other.Y
+	return dx*dx + dy*dy
+}
+
+// ValidateBounds checks that p lies on the canvas.
+func ValidateBounds(p Point) error {
+	if math.IsNaN(p.X) || math.IsNaN(p.Y) || p.X < 0 || p.Y < 0 || p.X > CanvasWidth || p.Y > CanvasHeight {
+		return ErrOutOfBounds
+	}
+
+	return nil
+}
+
+// Rect is an axis-aligned area of the canvas. Min is inclusive, Max is
+// exclusive.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// Grow returns r extended by d on every side.
+func (r Rect) Grow(d float64) Rect {
+	return Rect{
+		Min: Point{X: r.Min.X - d, Y: r.Min.Y - d},
+		Max: Point{X: r.Max.X + d, Y: r.Max.Y + d},
+	}
+}
+
+var tileCols, tileRows = int(math.Ceil(CanvasWidth / TileSize)), int(math.Ceil(CanvasHeight / TileSize))
+
+// TileCount is the number of tiles on the canvas. Tiles are numbered row
+// by row from 0.
+func TileCount() int {
+	return tileCols * tileRows
+}
+
+// TileBounds returns the area of the canvas covered by tile.
+func TileBounds(tile int) Rect {
+	col, row := tile%tileCols, tile/tileCols
+	return Rect{
+		Min: Point{X: float64(col) * TileSize, Y: float64(row) * TileSize},
+		Max: Point{X: math.Min(float64(col+1)*TileSize, CanvasWidth), Y: math.Min(float64(row+1)*TileSize, CanvasHeight)},
+	}
+}
+
+// TilesAround returns tile and the tiles next to it, in ascending order.
+func TilesAround(tile int) []int {
+	col, row := tile%tileCols, tile/tileCols
+	return tilesBetween(col-1, row-1, col+1, row+1)
+}
+
+// TilesNear returns the tiles holding a point closer than MinSpacing to p,
+// in ascending order. Any point placed in TileBounds(tile) has all of these
+// in TilesAround(tile).
+func TilesNear(p Point) []int {
+	return tilesBetween(
+		int((p.X-MinSpacing)/TileSize), int((p.Y-MinSpacing)/TileSize),
+		int((p.X+MinSpacing)/TileSize), int((p.Y+MinSpacing)/TileSize),
+	)
+}
+
+func tilesBetween(minCol, minRow, maxCol, maxRow int) []int {
+	minCol, minRow = max(minCol, 0), max(minRow, 0)
+	maxCol, maxRow = min(maxCol, tileCols-1), min(maxRow, tileRows-1)
+
+	tiles := make([]int, 0, 9)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			tiles = append(tiles, row*tileCols+col)
+		}
+	}
+
+	return tiles
+}
+
+type cell struct {
+	col, row int
+}
+
+// Grid is a spatial index that buckets points into MinSpacing-sized cells,
+// so spacing checks only look at the 3x3 cells around a point.
+type Grid struct {
+	cells map[cell][]Point
+}
+
+func NewGrid(points []Point) *Grid {
+	grid := &Grid{cells: make(map[cell][]Point, len(points))}
+	for _, p := range points {
+		grid.Insert(p)
+	}
+
+	return grid
+}
+
+func cellOf(p Point) cell {
+	return cell{col: int(p.X / MinSpacing), row: int(p.Y / MinSpacing)}
+}
+
+func (g *Grid) Insert(p Point) {
+	c := cellOf(p)
+	g.cells[c] = append(g.cells[c], p)
+}
+
+// IsFree reports whether p is at least MinSpacing away from every indexed
+// point.
+func (g *Grid) IsFree(p Point) bool {
+	center := cellOf(p)
+	for col := center.col - 1; col <= center.col+1; col++ {
+		for row := center.row - 1; row <= center.row+1; row++ {
+			for _, other := range g.cells[cell{col: col, row: row}] {
+				if p.distanceSquared(other) < MinSpacing*MinSpacing {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// FindFree picks a random free position on the canvas. Random sampling
+// keeps the party looking organic; once the canvas gets crowded it falls
+// back to scanning cell centers in random order.
+func (g *Grid) FindFree() (Point, error) {
+	return g.FindFreeIn(Rect{Max: Point{X: CanvasWidth, Y: CanvasHeight}})
+}
+
+// FindFreeIn is FindFree limited to area. The grid must hold every point
+// closer than MinSpacing to area.
+func (g *Grid) FindFreeIn(area Rect) (Point, error) {
+	width, height := area.Max.X-area.Min.X, area.Max.Y-area.Min.Y
+
+	for range randomAttempts {
+		p := Point{X: area.Min.X + rand.Float64()*width, Y: area.Min.Y + rand.Float64()*height}
+		if g.IsFree(p) {
+			return p, nil
+		}
+	}
+
+	minCol, minRow := int(area.Min.X/MinSpacing), int(area.Min.Y/MinSpacing)
+	cols, rows := int(math.Ceil(area.Max.X/MinSpacing))-minCol, int(math.Ceil(area.Max.Y/MinSpacing))-minRow
+	for _, i := range rand.Perm(cols * rows) {
+		p := Point{
+			X: (float64(minCol+i%cols) + 0.5) * MinSpacing,
+			Y: (float64(minRow+i/cols) + 0.5) * MinSpacing,
+		}
+		if p.X < area.Min.X || p.X >= area.Max.X || p.Y < area.Min.Y || p.Y >= area.Max.Y {
+			continue
+		}
+		if g.IsFree(p) {
+			return p, nil
+		}
+	}
+
+	return Point{}, ErrNoFreeSpace
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/omidnikrah/duckparty-backend/internal/config"
 	"github.com/omidnikrah/duckparty-backend/internal/handler"
 	"github.com/omidnikrah/duckparty-backend/internal/middleware"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
 	duckService "github.com/omidnikrah/duckparty-backend/internal/service/duck"
+	leaderboardService "github.com/omidnikrah/duckparty-backend/internal/service/leaderboard"
 	userService "github.com/omidnikrah/duckparty-backend/internal/service/user"
 	"github.com/omidnikrah/duckparty-backend/internal/storage"
 	ws "github.com/omidnikrah/duckparty-backend/internal/websocket"
@@ -17,13 +19,20 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, rdb *redis.Client, resendClient *resend.Client, r2Storage *storage.R2Storage, config *config.Config, broadcaster *ws.SocketBroadcaster) {
+func SetupRoutes(router *gin.Engine, db *gorm.DB, rdb *redis.Client, resendClient *resend.Client, fileStorage storage.Storage, config *config.Config, broadcaster *ws.SocketBroadcaster) {
 	userSvc := userService.NewService(db, rdb, resendClient, config)
-	duckSvc := duckService.NewService(db, userSvc, r2Storage, broadcaster)
+	cosmeticSvc := cosmeticService.NewService(db, config.Reactions)
+	duckSvc := duckService.NewService(db, userSvc, cosmeticSvc, fileStorage, broadcaster, config)
+	if err := duckSvc.CheckSprites(); err != nil {
+		panic("failed to load duck sprites: " + err.Error())
+	}
+	leaderboardSvc := leaderboardService.NewService(db, config.Reactions)
 
-	userHandler := handler.NewUserHandler(userSvc)
+	userHandler := handler.NewUserHandler(userSvc, cosmeticSvc)
 	duckHandler := handler.NewDuckHandler(duckSvc)
-	wsHandler := handler.NewWebSocketHandler(broadcaster)
+	cosmeticHandler := handler.NewCosmeticHandler(cosmeticSvc)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardSvc)
+	wsHandler := handler.NewWebSocketHandler(broadcaster, config)
 
 	apiRouter := router.Group(config.ApiPrefix)
 
@@ -45,11 +54,32 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, rdb *redis.Client, resendClien
 	authenticated.GET("/user", userHandler.GetMeUser)
 
 	v1Router.GET("/user/:userId/ducks", duckHandler.GetUserDucks)
-	v1Router.GET("/leaderboard", duckHandler.GetDucksLeaderboard)
+	v1Router.GET("/leaderboard", leaderboardHandler.GetLeaderboard)
+	v1Router.GET("/leaderboard/history", leaderboardHandler.GetLeaderboardHistory)
+	v1Router.GET("/reactions", duckHandler.GetReactionTypes)
 	v1Router.GET("/ducks", duckHandler.GetDucksList)
+	v1Router.GET("/ducks/viewport", duckHandler.GetDucksInViewport)
 	authenticated.POST("/duck", middleware.RateLimit(middleware.CreateRateLimit), duckHandler.CreateDuck)
 	authenticated.PUT("/duck/:duckId/reaction/:reaction", duckHandler.ReactionToDuck)
+	authenticated.DELETE("/duck/:duckId/reaction", duckHandler.RemoveReaction)
+	v1Router.GET("/duck/:duckId/reactions", duckHandler.GetDuckReactions)
+	authenticated.GET("/user/reactions", duckHandler.GetUserReactions)
+	authenticated.PUT("/duck/:duckId", middleware.RateLimit(middleware.CreateRateLimit), duckHandler.UpdateDuck)
 	authenticated.DELETE("/duck/:duckId", duckHandler.RemoveDuck)
+	authenticated.POST("/duck/:duckId/restore", duckHandler.RestoreDuck)
+	authenticated.GET("/user/trash", duckHandler.GetUserTrash)
+	v1Router.GET("/duck/:duckId/edits", duckHandler.GetDuckEdits)
+	authenticated.PATCH("/duck/:duckId/position", middleware.RateLimitByUserParam(middleware.MoveRateLimit, "duckId"), duckHandler.MoveDuck)
+
+	v1Router.GET("/cosmetics", cosmeticHandler.GetCosmetics)
+
+	admin := v1Router.Group("/admin")
+	admin.Use(middleware.AdminMiddleware(config))
+
+	admin.GET("/cosmetics", cosmeticHandler.GetAllCosmetics)
+	admin.POST("/cosmetics", cosmeticHandler.CreateCosmetic)
+	admin.DELETE("/cosmetics/:cosmeticId", cosmeticHandler.RetireCosmetic)
+	admin.GET("/websocket/stats", wsHandler.GetStats)
 
 	v1Router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
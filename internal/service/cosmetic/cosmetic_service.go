@@ -0,0 +1,206 @@
+package cosmeticService
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"gorm.io/gorm"
+)
+
+// catalogCacheTTL bounds how long other replicas keep validating against a
+// catalog after an admin change.
+const catalogCacheTTL = 30 * time.Second
+
+var (
+	ErrCosmeticNotFound       = errors.New("cosmetic not found")
+	ErrCosmeticExists         = errors.New("cosmetic already exists")
+	ErrMissingUnlockThreshold = errors.New("unlock rule requires an unlock threshold")
+)
+
+type CosmeticService struct {
+	db        *gorm.DB
+	reactions *types.ReactionSet
+
+	mu       sync.Mutex
+	catalog  types.AppearanceCatalog
+	loadedAt time.Time
+}
+
+type CreateCosmeticRequest struct {
+	ID              string
+	Slot            types.CosmeticSlot
+	DisplayName     string
+	AssetURL        string
+	Rarity          model.CosmeticRarity
+	AvailableFrom   *time.Time
+	AvailableUntil  *time.Time
+	UnlockRule      model.CosmeticUnlockRule
+	UnlockThreshold int
+}
+
+func NewService(db *gorm.DB, reactions *types.ReactionSet) *CosmeticService {
+	return &CosmeticService{db: db, reactions: reactions}
+}
+
+func (s *CosmeticService) availableAt(at time.Time) *gorm.DB {
+	return s.db.
+		Where("available_from IS NULL OR available_from <= ?", at).
+		Where("available_until IS NULL OR available_until > ?", at)
+}
+
+// GetAvailableCosmetics returns the cosmetics that can be used right now.
+func (s *CosmeticService) GetAvailableCosmetics() ([]model.Cosmetic, error) {
+	cosmetics := []model.Cosmetic{}
+	if err := s.availableAt(time.Now()).Order("slot").Order("id").Find(&cosmetics).Error; err != nil {
+		return nil, err
+	}
+
+	return cosmetics, nil
+}
+
+// GetAllCosmetics includes retired and upcoming cosmetics.
+func (s *CosmeticService) GetAllCosmetics() ([]model.Cosmetic, error) {
+	cosmetics := []model.Cosmetic{}
+	if err := s.db.Order("slot").Order("id").Find(&cosmetics).Error; err != nil {
+		return nil, err
+	}
+
+	return cosmetics, nil
+}
+
+// Catalog returns the currently available cosmetics for appearance
+// validation. It is cached briefly so validation does not hit the database
+// on every request.
+func (s *CosmeticService) Catalog() (types.AppearanceCatalog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.catalog != nil && time.Since(s.loadedAt) < catalogCacheTTL {
+		return s.catalog, nil
+	}
+
+	var cosmetics []model.Cosmetic
+	if err := s.availableAt(time.Now()).Select("id", "slot").Find(&cosmetics).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make(map[string]types.CosmeticSlot, len(cosmetics))
+	for _, cosmetic := range cosmetics {
+		slots[cosmetic.ID] = cosmetic.Slot
+	}
+
+	s.catalog = types.NewCatalog(slots)
+	s.loadedAt = time.Now()
+
+	return s.catalog, nil
+}
+
+// CatalogWith returns the catalog together with the cosmetics worn in
+// appearance, even retired ones, so a duck still wearing them can be edited
+// without taking them off.
+func (s *CosmeticService) CatalogWith(worn types.DuckAppearance) (types.AppearanceCatalog, error) {
+	catalog, err := s.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	if worn.Skin != "" && !catalog.HasSkin(worn.Skin) {
+		missing = append(missing, string(worn.Skin))
+	}
+	for _, accessory := range worn.Accessories {
+		if _, ok := catalog.AccessorySlot(accessory); !ok {
+			missing = append(missing, string(accessory))
+		}
+	}
+
+	if len(missing) == 0 {
+		return catalog, nil
+	}
+
+	var cosmetics []model.Cosmetic
+	if err := s.db.Select("id", "slot").Where("id IN ?", missing).Find(&cosmetics).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make(map[string]types.CosmeticSlot, len(cosmetics))
+	for _, cosmetic := range cosmetics {
+		slots[cosmetic.ID] = cosmetic.Slot
+	}
+
+	return types.ExtendCatalog(catalog, slots), nil
+}
+
+func (s *CosmeticService) invalidateCatalog() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.catalog = nil
+}
+
+func (s *CosmeticService) CreateCosmetic(req CreateCosmeticRequest) (*model.Cosmetic, error) {
+	rarity := req.Rarity
+	if rarity == "" {
+		rarity = model.RarityCommon
+	}
+
+	unlockRule := req.UnlockRule
+	if unlockRule == "" {
+		unlockRule = model.UnlockFree
+	}
+
+	if (unlockRule == model.UnlockTopLeaderboard || unlockRule == model.UnlockLikesReceived) && req.UnlockThreshold <= 0 {
+		return nil, ErrMissingUnlockThreshold
+	}
+
+	cosmetic := model.Cosmetic{
+		ID:              req.ID,
+		Slot:            req.Slot,
+		DisplayName:     req.DisplayName,
+		AssetURL:        req.AssetURL,
+		Rarity:          rarity,
+		AvailableFrom:   req.AvailableFrom,
+		AvailableUntil:  req.AvailableUntil,
+		UnlockRule:      unlockRule,
+		UnlockThreshold: req.UnlockThreshold,
+	}
+
+	result := s.db.Where(model.Cosmetic{ID: req.ID}).Attrs(cosmetic).FirstOrCreate(&cosmetic)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCosmeticExists
+	}
+
+	s.invalidateCatalog()
+
+	return &cosmetic, nil
+}
+
+// RetireCosmetic ends the availability window of a cosmetic now. Ducks that
+// already wear it keep it, but it can no longer be picked.
+func (s *CosmeticService) RetireCosmetic(id string) (*model.Cosmetic, error) {
+	var cosmetic model.Cosmetic
+	if err := s.db.First(&cosmetic, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCosmeticNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if cosmetic.AvailableUntil == nil || cosmetic.AvailableUntil.After(now) {
+		if err := s.db.Model(&cosmetic).Update("available_until", now).Error; err != nil {
+			return nil, err
+		}
+		cosmetic.AvailableUntil = &now
+	}
+
+	s.invalidateCatalog()
+
+	return &cosmetic, nil
+}
@@ -0,0 +1,143 @@
+package cosmeticService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCosmeticLocked = errors.New("cosmetic not unlocked")
+
+// InventoryItem is a cosmetic the user may use. UnlockedAt is nil for free
+// cosmetics.
+type InventoryItem struct {
+	Cosmetic   model.Cosmetic `json:"cosmetic"`
+	UnlockedAt *time.Time     `json:"unlocked_at,omitempty"`
+}
+
+// unlockRuleConditions select, for each rule, the cosmetics userID meets the
+// condition for.
+func (s *CosmeticService) unlockRuleConditions(userID uint) map[model.CosmeticUnlockRule]clause.Expr {
+	return map[model.CosmeticUnlockRule]clause.Expr{
+		model.UnlockFirstDuck: gorm.Expr(`EXISTS (SELECT 1 FROM ducks WHERE ducks.owner_id = ?)`, userID),
+		model.UnlockTopLeaderboard: gorm.Expr(`EXISTS (SELECT 1 FROM ducks WHERE ducks.owner_id = ? AND ducks.deleted_at IS NULL
+			AND ducks.rank BETWEEN 1 AND cosmetics.unlock_threshold)`, userID),
+		// Every positive reaction counts as a like.
+		model.UnlockLikesReceived: gorm.Expr(`(SELECT COALESCE(SUM(?), 0) FROM ducks WHERE ducks.owner_id = ? AND ducks.deleted_at IS NULL)
+			>= cosmetics.unlock_threshold`, s.reactions.PositiveCount("ducks.reaction_counts"), userID),
+	}
+}
+
+// GrantUnlocks adds every cosmetic userID has earned but not yet unlocked to
+// their inventory.
+func (s *CosmeticService) GrantUnlocks(userID uint) error {
+	for rule, condition := range s.unlockRuleConditions(userID) {
+		err := s.db.Exec(`INSERT INTO user_cosmetics (user_id, cosmetic_id, unlocked_at)
+			SELECT ?, cosmetics.id, NOW() FROM cosmetics
+			WHERE cosmetics.unlock_rule = ? AND ?
+			ON CONFLICT DO NOTHING`, userID, rule, condition).Error
+		if err != nil {
+			return fmt.Errorf("grant %s cosmetics: %w", rule, err)
+		}
+	}
+
+	return nil
+}
+
+// GrantLeaderboardUnlocks grants top_leaderboard cosmetics to the owners of
+// every duck ranked high enough, in one statement.
+func (s *CosmeticService) GrantLeaderboardUnlocks(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Exec(`INSERT INTO user_cosmetics (user_id, cosmetic_id, unlocked_at)
+		SELECT DISTINCT ducks.owner_id, cosmetics.id, NOW() FROM cosmetics
+		JOIN ducks ON ducks.deleted_at IS NULL AND ducks.rank BETWEEN 1 AND cosmetics.unlock_threshold
+		WHERE cosmetics.unlock_rule = ?
+		ON CONFLICT DO NOTHING`, model.UnlockTopLeaderboard)
+
+	return result.RowsAffected, result.Error
+}
+
+// GetInventory returns the free cosmetics plus the ones userID unlocked.
+func (s *CosmeticService) GetInventory(userID uint) ([]InventoryItem, error) {
+	var cosmetics []model.Cosmetic
+	if err := s.db.Where("unlock_rule = ?", model.UnlockFree).Order("slot").Order("id").Find(&cosmetics).Error; err != nil {
+		return nil, err
+	}
+
+	var unlocked []model.UserCosmetic
+	if err := s.db.Preload("Cosmetic").Where("user_id = ?", userID).Order("unlocked_at").Find(&unlocked).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]InventoryItem, 0, len(cosmetics)+len(unlocked))
+	for _, cosmetic := range cosmetics {
+		items = append(items, InventoryItem{Cosmetic: cosmetic})
+	}
+	for _, entry := range unlocked {
+		if entry.Cosmetic.UnlockRule == model.UnlockFree {
+			continue
+		}
+		unlockedAt := entry.UnlockedAt
+		items = append(items, InventoryItem{Cosmetic: entry.Cosmetic, UnlockedAt: &unlockedAt})
+	}
+
+	return items, nil
+}
+
+// CheckUnlocked returns ErrCosmeticLocked when appearance uses a cosmetic
+// userID has not unlocked. Cosmetics listed in allowed are accepted anyway,
+// so ducks keep what they already wear. tx may be nil.
+func (s *CosmeticService) CheckUnlocked(tx *gorm.DB, userID uint, appearance types.DuckAppearance, allowed types.DuckAppearance) error {
+	db := s.db
+	if tx != nil {
+		db = tx
+	}
+
+	kept := make(map[string]struct{}, len(allowed.Accessories)+1)
+	kept[string(allowed.Skin)] = struct{}{}
+	for _, accessory := range allowed.Accessories {
+		kept[string(accessory)] = struct{}{}
+	}
+
+	ids := make([]string, 0, len(appearance.Accessories)+1)
+	for _, id := range append([]string{string(appearance.Skin)}, accessoryIDs(appearance)...) {
+		if _, ok := kept[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var locked []string
+	err := db.Model(&model.Cosmetic{}).
+		Where("cosmetics.id IN ? AND cosmetics.unlock_rule <> ?", ids, model.UnlockFree).
+		Where("NOT EXISTS (SELECT 1 FROM user_cosmetics WHERE user_cosmetics.cosmetic_id = cosmetics.id AND user_cosmetics.user_id = ?)", userID).
+		Order("cosmetics.id").
+		Pluck("cosmetics.id", &locked).Error
+	if err != nil {
+		return err
+	}
+
+	if len(locked) > 0 {
+		return fmt.Errorf("%w: %s", ErrCosmeticLocked, strings.Join(locked, ", "))
+	}
+
+	return nil
+}
+
+func accessoryIDs(appearance types.DuckAppearance) []string {
+	ids := make([]string, 0, len(appearance.Accessories))
+	for _, accessory := range appearance.Accessories {
+		ids = append(ids, string(accessory))
+	}
+
+	return ids
+}
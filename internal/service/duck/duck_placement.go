@@ -0,0 +1,130 @@
+package duckService
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/placement"
+	"gorm.io/gorm"
+)
+
+// placementLockKey namespaces the Postgres advisory locks taken per canvas
+// tile, so two concurrent requests cannot claim spots too close together.
+// Two placements closer than placement.MinSpacing always share a locked
+// tile.
+const placementLockKey = 0x6475636b
+
+const (
+	// Tiles locked by other placements are retried this many times before
+	// giving up, pausing in between.
+	placementBusyAttempts = 3
+	placementBusyDelay    = 20 * time.Millisecond
+)
+
+// placeDuck validates a proposed position, or picks a free one when none is
+// given. excludeID skips a duck that is being moved. It must run inside a
+// transaction.
+func (s *DuckService) placeDuck(tx *gorm.DB, proposed *placement.Point, excludeID uint) (placement.Point, error) {
+	if proposed == nil {
+		return s.findFreePosition(tx, excludeID)
+	}
+
+	position := *proposed
+	if err := placement.ValidateBounds(position); err != nil {
+		return placement.Point{}, err
+	}
+
+	// Tiles are locked in ascending order, so waiting here cannot deadlock
+	// with another proposed placement.
+	for _, tile := range placement.TilesNear(position) {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", placementLockKey, tile).Error; err != nil {
+			return placement.Point{}, err
+		}
+	}
+
+	var neighbours int64
+	err := tx.Model(&model.Duck{}).
+		Where("id <> ?", excludeID).
+		Where("x BETWEEN ? AND ? AND y BETWEEN ? AND ?",
+			position.X-placement.MinSpacing, position.X+placement.MinSpacing,
+			position.Y-placement.MinSpacing, position.Y+placement.MinSpacing).
+		Where("(x - ?) * (x - ?) + (y - ?) * (y - ?) < ?",
+			position.X, position.X, position.Y, position.Y, placement.MinSpacing*placement.MinSpacing).
+		Count(&neighbours).Error
+	if err != nil {
+		return placement.Point{}, err
+	}
+
+	if neighbours > 0 {
+		return placement.Point{}, placement.ErrTooClose
+	}
+
+	return position, nil
+}
+
+// findFreePosition looks for a free spot one tile at a time, in random
+// order, loading only the ducks in and around that tile. Tiles another
+// placement is working on are skipped and retried later: waiting for them
+// while already holding tile locks could deadlock.
+func (s *DuckService) findFreePosition(tx *gorm.DB, excludeID uint) (placement.Point, error) {
+	pending := rand.Perm(placement.TileCount())
+
+	for attempt := 0; attempt < placementBusyAttempts && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			time.Sleep(placementBusyDelay)
+		}
+
+		var busy []int
+		for _, tile := range pending {
+			locked, err := tryLockTiles(tx, placement.TilesAround(tile))
+			if err != nil {
+				return placement.Point{}, err
+			}
+			if !locked {
+				busy = append(busy, tile)
+				continue
+			}
+
+			bounds := placement.TileBounds(tile)
+			near := bounds.Grow(placement.MinSpacing)
+
+			var points []placement.Point
+			if err := tx.Model(&model.Duck{}).
+				Where("id <> ?", excludeID).
+				Where("x >= ? AND x < ? AND y >= ? AND y < ?", near.Min.X, near.Max.X, near.Min.Y, near.Max.Y).
+				Select("x", "y").
+				Find(&points).Error; err != nil {
+				return placement.Point{}, err
+			}
+
+			position, err := placement.NewGrid(points).FindFreeIn(bounds)
+			if errors.Is(err, placement.ErrNoFreeSpace) {
+				continue
+			}
+
+			return position, err
+		}
+
+		pending = busy
+	}
+
+	return placement.Point{}, placement.ErrNoFreeSpace
+}
+
+// tryLockTiles takes the advisory locks of tiles without waiting. It reports
+// false as soon as one of them is held by another transaction.
+func tryLockTiles(tx *gorm.DB, tiles []int) (bool, error) {
+	for _, tile := range tiles {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?, ?)", placementLockKey, tile).Scan(&locked).Error; err != nil {
+			return false, err
+		}
+		if !locked {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,137 @@
+package duckService
+
+import (
+	"errors"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"github.com/omidnikrah/duckparty-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReactionsPageSize = 20
+	maxReactionsPageSize     = 100
+)
+
+type ReactionListFilter struct {
+	Cursor   string
+	Limit    int
+	Reaction types.ReactionType
+}
+
+type ReactionListPage struct {
+	Items      []model.DuckReactions
+	NextCursor string
+	HasMore    bool
+}
+
+// adjustReactionCount changes one reaction count of a duck by delta in a
+// single statement, so concurrent reactions never overwrite each other.
+// Counts that drop to zero are removed from the map. It returns the updated
+// duck.
+func adjustReactionCount(tx *gorm.DB, duckId uint, reaction types.ReactionType, delta int64) (model.Duck, error) {
+	var duck model.Duck
+
+	result := tx.Raw(`UPDATE ducks SET
+			reaction_counts = CASE
+				WHEN COALESCE((reaction_counts->>CAST(@reaction AS text))::bigint, 0) + CAST(@delta AS bigint) > 0
+				THEN jsonb_set(reaction_counts, ARRAY[CAST(@reaction AS text)], to_jsonb(COALESCE((reaction_counts->>CAST(@reaction AS text))::bigint, 0) + CAST(@delta AS bigint)))
+				ELSE reaction_counts - CAST(@reaction AS text)
+			END,
+			updated_at = NOW()
+		WHERE id = @duck AND deleted_at IS NULL
+		RETURNING *`, map[string]interface{}{
+		"reaction": string(reaction),
+		"delta":    delta,
+		"duck":     duckId,
+	}).Scan(&duck)
+	if result.Error != nil {
+		return duck, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return duck, ErrDuckNotFound
+	}
+
+	return duck, nil
+}
+
+// GetDuckReactions returns who reacted to a duck, newest first. Pages are
+// keyed by reaction time and user ID.
+func (s *DuckService) GetDuckReactions(duckId uint, filter ReactionListFilter) (*ReactionListPage, error) {
+	var duck model.Duck
+	if err := s.db.Select("id").First(&duck, duckId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuckNotFound
+		}
+		return nil, err
+	}
+
+	query := s.db.Preload("User").Where("duck_reactions.duck_id = ?", duckId)
+
+	return s.listReactions(query, filter, "duck_reactions.user_id", func(reaction model.DuckReactions) uint {
+		return reaction.UserID
+	})
+}
+
+// GetUserReactions returns the ducks userId reacted to, newest reaction
+// first. Reactions to removed ducks are left out.
+func (s *DuckService) GetUserReactions(userId uint, filter ReactionListFilter) (*ReactionListPage, error) {
+	query := s.db.
+		Preload("Duck.Owner").
+		Joins("JOIN ducks ON ducks.id = duck_reactions.duck_id AND ducks.deleted_at IS NULL").
+		Where("duck_reactions.user_id = ?", userId)
+
+	return s.listReactions(query, filter, "duck_reactions.duck_id", func(reaction model.DuckReactions) uint {
+		return reaction.DuckID
+	})
+}
+
+// listReactions pages through query by (created_at, idColumn), where
+// idColumn tells reactions within the listing apart.
+func (s *DuckService) listReactions(query *gorm.DB, filter ReactionListFilter, idColumn string, cursorID func(model.DuckReactions) uint) (*ReactionListPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultReactionsPageSize
+	}
+	if limit > maxReactionsPageSize {
+		limit = maxReactionsPageSize
+	}
+
+	if filter.Reaction != "" {
+		if !s.reactions.IsValid(filter.Reaction) {
+			return nil, ErrUnknownReaction
+		}
+		query = query.Where("duck_reactions.reaction = ?", filter.Reaction)
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := utils.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(duck_reactions.created_at, "+idColumn+") < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	reactions := []model.DuckReactions{}
+	if err := query.
+		Order("duck_reactions.created_at DESC").
+		Order(idColumn + " DESC").
+		Limit(limit + 1).
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	page := &ReactionListPage{Items: reactions}
+
+	if len(reactions) > limit {
+		page.Items = reactions[:limit]
+		page.HasMore = true
+
+		last := page.Items[limit-1]
+		page.NextCursor = utils.EncodeCursor(utils.Cursor{CreatedAt: last.CreatedAt, ID: cursorID(last)})
+	}
+
+	return page, nil
+}
@@ -1,35 +1,71 @@
 package duckService
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/config"
+	duck_dto "github.com/omidnikrah/duckparty-backend/internal/dto/duck"
+	"github.com/omidnikrah/duckparty-backend/internal/imaging"
 	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/placement"
+	cosmeticService "github.com/omidnikrah/duckparty-backend/internal/service/cosmetic"
 	userService "github.com/omidnikrah/duckparty-backend/internal/service/user"
 	"github.com/omidnikrah/duckparty-backend/internal/storage"
 	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"github.com/omidnikrah/duckparty-backend/internal/utils"
 	"github.com/omidnikrah/duckparty-backend/internal/websocket"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultDucksPageSize = 20
+	maxDucksPageSize     = 100
 )
 
 var (
 	ErrDuckNotFound       = errors.New("duck not found")
 	ErrDuckAlreadyReacted = errors.New("duck already reacted")
+	ErrUnknownReaction    = errors.New("unknown reaction type")
+	ErrInvalidAppearance  = errors.New("invalid appearance")
+	ErrDuckRestoreExpired = errors.New("duck was removed too long ago to be restored")
+	// ErrImageUploadsDisabled is returned for uploads while images are
+	// only rendered on the server.
+	ErrImageUploadsDisabled = errors.New("image uploads are disabled; images are rendered from the appearance")
+	// ErrRenderingUnavailable is returned when a duck image has to be
+	// rendered but a sprite it needs is missing on this server.
+	ErrRenderingUnavailable = errors.New("duck images cannot be rendered right now")
 )
 
 type DuckService struct {
-	db          *gorm.DB
-	userService *userService.UserService
-	storage     *storage.R2Storage
-	broadcaster *websocket.SocketBroadcaster
+	db              *gorm.DB
+	userService     *userService.UserService
+	storage         storage.Storage
+	broadcaster     *websocket.SocketBroadcaster
+	cosmeticService *cosmeticService.CosmeticService
+	compositor      *imaging.Compositor
+	reactions       *types.ReactionSet
+	// allowImageUploads lets clients send their own image instead of
+	// having it rendered from the appearance.
+	allowImageUploads bool
 }
 
-func NewService(db *gorm.DB, userService *userService.UserService, r2Storage *storage.R2Storage, broadcaster *websocket.SocketBroadcaster) *DuckService {
+func NewService(db *gorm.DB, userService *userService.UserService, cosmeticService *cosmeticService.CosmeticService, fileStorage storage.Storage, broadcaster *websocket.SocketBroadcaster, config *config.Config) *DuckService {
 	return &DuckService{
-		db:          db,
-		userService: userService,
-		storage:     r2Storage,
-		broadcaster: broadcaster,
+		db:                db,
+		userService:       userService,
+		cosmeticService:   cosmeticService,
+		storage:           fileStorage,
+		broadcaster:       broadcaster,
+		compositor:        imaging.NewCompositor(config.SpritesDir),
+		reactions:         config.Reactions,
+		allowImageUploads: config.AllowImageUploads,
 	}
 }
 
@@ -38,22 +74,118 @@ type CreateDuckRequest struct {
 	Email          string
 	OwnerId        uint
 	AppearanceJSON string
-	ImageData      []byte
+	// ImageData is optional; without it the image is rendered from the
+	// appearance.
+	ImageData []byte
+	// Position is where the client wants the duck; nil lets the server
+	// pick a free spot.
+	Position *placement.Point
 }
 
 type ReactToDuckRequest struct {
 	DuckID   uint
 	UserID   uint
-	Reaction model.ReactionType
+	Reaction types.ReactionType
 }
 
-func (s *DuckService) CreateDuck(req CreateDuckRequest) (*model.Duck, error) {
+type DuckListFilter struct {
+	Cursor        string
+	Limit         int
+	Skin          types.SkinType
+	Accessory     types.AccessoryType
+	OwnerID       uint
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+type DuckListPage struct {
+	Items      []model.Duck
+	NextCursor string
+	HasMore    bool
+}
+
+type UpdateDuckRequest struct {
+	DuckID         uint
+	UserID         uint
+	Name           string
+	AppearanceJSON string
+	// ImageData replaces the duck's image when set. Otherwise the image is
+	// re-rendered if the appearance changed.
+	ImageData []byte
+}
+
+// parseAppearance decodes and validates an appearance against the catalog
+// plus the cosmetics in worn, which a duck may keep after they are retired.
+// The catalog is also returned for rendering. Catalog violations come back
+// as validator.ValidationErrors.
+func (s *DuckService) parseAppearance(appearanceJSON string, worn types.DuckAppearance) (types.DuckAppearance, types.AppearanceCatalog, error) {
 	var appearance types.DuckAppearance
-	if err := json.Unmarshal([]byte(req.AppearanceJSON), &appearance); err != nil {
+	if err := json.Unmarshal([]byte(appearanceJSON), &appearance); err != nil {
+		return appearance, nil, fmt.Errorf("%w: %v", ErrInvalidAppearance, err)
+	}
+
+	catalog, err := s.cosmeticService.CatalogWith(worn)
+	if err != nil {
+		return appearance, nil, err
+	}
+
+	if err := types.ValidateAppearance(context.Background(), appearance, catalog); err != nil {
+		return appearance, nil, err
+	}
+
+	return appearance, catalog, nil
+}
+
+// CheckSprites makes sure the sprites of every cosmetic, retired ones
+// included, can be loaded.
+func (s *DuckService) CheckSprites() error {
+	cosmetics, err := s.cosmeticService.GetAllCosmetics()
+	if err != nil {
+		return err
+	}
+
+	slots := make(map[string]types.CosmeticSlot, len(cosmetics))
+	for _, cosmetic := range cosmetics {
+		slots[cosmetic.ID] = cosmetic.Slot
+	}
+
+	return s.compositor.Check(slots)
+}
+
+// processDuckImage uses the uploaded image when there is one and uploads
+// are allowed, and otherwise renders the appearance.
+func (s *DuckService) processDuckImage(imageData []byte, appearance types.DuckAppearance, catalog types.AppearanceCatalog) (*imaging.ProcessedImage, error) {
+	if len(imageData) > 0 {
+		if !s.allowImageUploads {
+			return nil, ErrImageUploadsDisabled
+		}
+		return imaging.Process(imageData)
+	}
+
+	rendered, err := s.compositor.Render(appearance, catalog)
+	if errors.Is(err, imaging.ErrMissingSprite) {
+		slog.Default().Error("failed to render duck image", "error", err)
+		return nil, ErrRenderingUnavailable
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	imageURL, err := s.storage.UploadFile(req.ImageData, req.Name)
+	return imaging.ProcessImage(rendered)
+}
+
+func (s *DuckService) CreateDuck(req CreateDuckRequest) (*model.Duck, error) {
+	appearance, catalog, err := s.parseAppearance(req.AppearanceJSON, types.DuckAppearance{})
+	if err != nil {
+		return nil, err
+	}
+
+	processedImage, err := s.processDuckImage(req.ImageData, appearance, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.uploadDuckImages(req.Name, processedImage)
 	if err != nil {
 		return nil, err
 	}
@@ -74,11 +206,25 @@ func (s *DuckService) CreateDuck(req CreateDuckRequest) (*model.Duck, error) {
 			return err
 		}
 
+		if err := s.cosmeticService.CheckUnlocked(tx, user.ID, appearance, types.DuckAppearance{}); err != nil {
+			return err
+		}
+
+		position, err := s.placeDuck(tx, req.Position, 0)
+		if err != nil {
+			return err
+		}
+
 		newDuck = model.Duck{
-			OwnerID:    user.ID,
-			Name:       req.Name,
-			Appearance: appearance,
-			Image:      imageURL,
+			OwnerID:        user.ID,
+			Name:           req.Name,
+			X:              position.X,
+			Y:              position.Y,
+			Appearance:     appearance,
+			Image:          images.Original,
+			ImageMedium:    images.Medium,
+			ImageThumbnail: images.Thumbnail,
+			ReactionCounts: model.ReactionCounts{},
 		}
 
 		if err := tx.Create(&newDuck).Error; err != nil {
@@ -93,27 +239,163 @@ func (s *DuckService) CreateDuck(req CreateDuckRequest) (*model.Duck, error) {
 	})
 
 	if err != nil {
+		s.deleteStoredImages(images.Original, images.Medium, images.Thumbnail)
 		return nil, err
 	}
 
 	if s.broadcaster != nil {
-		notification := websocket.NewNotification(websocket.NotificationTypeNewDuck, newDuck)
+		notification := websocket.NewNotification(websocket.NotificationTypeNewDuck, duck_dto.NewDuckResponse(newDuck), websocket.TopicDucks, websocket.UserTopic(newDuck.OwnerID))
 		s.broadcaster.Broadcast(notification)
 	}
 
+	s.grantUnlocks(newDuck.OwnerID)
+
 	return &newDuck, nil
 }
 
+// UpdateDuck lets an owner change a duck's name, appearance and optionally
+// its image. Each edit is kept in the duck's edit history.
+func (s *DuckService) UpdateDuck(req UpdateDuckRequest) (*model.Duck, error) {
+	var current model.Duck
+	if err := s.db.Select("id", "appearance").Where("id = ? AND owner_id = ?", req.DuckID, req.UserID).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuckNotFound
+		}
+		return nil, err
+	}
+
+	appearance, catalog, err := s.parseAppearance(req.AppearanceJSON, current.Appearance)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a new upload the image is re-rendered only when the
+	// appearance changed, so a renamed duck keeps its picture. It is also
+	// kept when the new appearance cannot be rendered, rather than failing
+	// the whole edit.
+	var images *duckImageURLs
+	if len(req.ImageData) > 0 || !reflect.DeepEqual(current.Appearance, appearance) {
+		processedImage, err := s.processDuckImage(req.ImageData, appearance, catalog)
+		switch {
+		case errors.Is(err, ErrRenderingUnavailable):
+		case err != nil:
+			return nil, err
+		default:
+			images, err = s.uploadDuckImages(req.Name, processedImage)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var (
+		duck      model.Duck
+		oldImages []string
+		renamed   bool
+	)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", req.DuckID, req.UserID).First(&duck).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDuckNotFound
+			}
+			return err
+		}
+
+		if err := s.cosmeticService.CheckUnlocked(tx, req.UserID, appearance, duck.Appearance); err != nil {
+			return err
+		}
+
+		edit := model.DuckEdit{
+			DuckID:             duck.ID,
+			EditorID:           req.UserID,
+			PreviousName:       duck.Name,
+			Name:               req.Name,
+			PreviousAppearance: duck.Appearance,
+			Appearance:         appearance,
+			PreviousImage:      duck.Image,
+			Image:              duck.Image,
+		}
+
+		renamed = duck.Name != req.Name
+		duck.Name = req.Name
+		duck.Appearance = appearance
+
+		if images != nil {
+			// The replaced original stays in storage: the edit history
+			// links to it as the previous image.
+			oldImages = []string{duck.ImageMedium, duck.ImageThumbnail}
+			duck.Image = images.Original
+			duck.ImageMedium = images.Medium
+			duck.ImageThumbnail = images.Thumbnail
+			edit.Image = images.Original
+		}
+
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&duck).Select("name", "appearance", "image", "image_medium", "image_thumbnail").Updates(&duck).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Owner").First(&duck, duck.ID).Error
+	})
+
+	if err != nil {
+		if images != nil {
+			s.deleteStoredImages(images.Original, images.Medium, images.Thumbnail)
+		}
+		return nil, err
+	}
+
+	s.deleteStoredImages(oldImages...)
+
+	if s.broadcaster != nil {
+		topics := []string{websocket.TopicDucks, websocket.DuckTopic(duck.ID), websocket.UserTopic(duck.OwnerID)}
+		s.broadcaster.Broadcast(websocket.NewNotification(websocket.NotificationTypeDuckUpdated, duck_dto.NewDuckResponse(duck), topics...))
+
+		if renamed {
+			s.broadcaster.Broadcast(websocket.NewNotification(websocket.NotificationTypeDuckRenamed, websocket.DuckRenamedData{
+				DuckID: duck.ID,
+				Name:   duck.Name,
+			}, topics...))
+		}
+	}
+
+	return &duck, nil
+}
+
+func (s *DuckService) GetDuckEdits(duckId uint) ([]model.DuckEdit, error) {
+	if err := s.db.Select("id").First(&model.Duck{}, duckId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuckNotFound
+		}
+		return nil, err
+	}
+
+	edits := []model.DuckEdit{}
+	if err := s.db.Where("duck_id = ?", duckId).Order("created_at DESC").Order("id DESC").Find(&edits).Error; err != nil {
+		return nil, err
+	}
+
+	return edits, nil
+}
+
 func (s *DuckService) ReactionToDuck(req ReactToDuckRequest) (*model.DuckReactions, error) {
 	var (
 		reaction model.DuckReactions
 		duck     model.Duck
 	)
 
+	if !s.reactions.IsValid(req.Reaction) {
+		return nil, ErrUnknownReaction
+	}
+
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		var existingReaction model.DuckReactions
 
-		if err := tx.Preload("Owner").First(&duck, req.DuckID).Error; err != nil {
+		if err := tx.Select("id", "owner_id").First(&duck, req.DuckID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return ErrDuckNotFound
 			}
@@ -125,11 +407,18 @@ func (s *DuckService) ReactionToDuck(req ReactToDuckRequest) (*model.DuckReactio
 				return ErrDuckAlreadyReacted
 			}
 
-			if err := tx.Delete(&existingReaction).Error; err != nil {
-				return err
+			result := tx.Where("duck_id = ? AND user_id = ? AND reaction = ?", req.DuckID, req.UserID, existingReaction.Reaction).
+				Delete(&model.DuckReactions{})
+			if result.Error != nil {
+				return result.Error
 			}
 
-			updateReactionCounts(&duck, existingReaction.Reaction, -1)
+			// A concurrent request may have taken the reaction back already.
+			if result.RowsAffected > 0 {
+				if _, err := adjustReactionCount(tx, req.DuckID, existingReaction.Reaction, -1); err != nil {
+					return err
+				}
+			}
 		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
@@ -147,12 +436,12 @@ func (s *DuckService) ReactionToDuck(req ReactToDuckRequest) (*model.DuckReactio
 			return err
 		}
 
-		updateReactionCounts(&duck, req.Reaction, 1)
-
-		if err := tx.Save(&duck).Error; err != nil {
+		updated, err := adjustReactionCount(tx, req.DuckID, req.Reaction, 1)
+		if err != nil {
 			return err
 		}
 
+		duck = updated
 		reaction.Duck = duck
 
 		return nil
@@ -162,35 +451,145 @@ func (s *DuckService) ReactionToDuck(req ReactToDuckRequest) (*model.DuckReactio
 		return nil, err
 	}
 
+	if s.broadcaster != nil {
+		s.broadcastReactionCounts(&duck)
+
+		if duck.OwnerID != req.UserID {
+			s.broadcaster.Broadcast(websocket.NewNotification(websocket.NotificationTypeReactionReceived, websocket.ReactionReceivedData{
+				DuckID:    duck.ID,
+				ReactorID: req.UserID,
+				Reaction:  string(req.Reaction),
+			}, websocket.UserTopic(duck.OwnerID)))
+		}
+	}
+
+	if !s.reactions.IsNegative(req.Reaction) && duck.OwnerID != req.UserID {
+		s.grantUnlocks(duck.OwnerID)
+	}
+
 	return &reaction, nil
 }
 
-func (s *DuckService) GetDucksList() (*[]model.Duck, error) {
-	ducks := []model.Duck{}
-	if err := s.db.Preload("Owner").Order("created_at DESC").Find(&ducks).Error; err != nil {
+// RemoveReaction takes back the user's reaction to a duck. Removing a
+// reaction that does not exist is not an error, so retries are safe.
+func (s *DuckService) RemoveReaction(userId uint, duckId uint) (*model.Duck, error) {
+	var (
+		duck    model.Duck
+		removed bool
+	)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&duck, duckId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDuckNotFound
+			}
+			return err
+		}
+
+		var reaction model.DuckReactions
+		result := tx.Clauses(clause.Returning{}).
+			Where("duck_id = ? AND user_id = ?", duckId, userId).
+			Delete(&reaction)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		updated, err := adjustReactionCount(tx, duckId, reaction.Reaction, -1)
+		if err != nil {
+			return err
+		}
+
+		duck = updated
+		removed = true
+
+		return nil
+	})
+
+	if err != nil {
 		return nil, err
 	}
 
-	return &ducks, nil
+	if removed && s.broadcaster != nil {
+		s.broadcastReactionCounts(&duck)
+	}
+
+	return &duck, nil
+}
+
+// ReactionSet returns the reactions users can leave on ducks.
+func (s *DuckService) ReactionSet() *types.ReactionSet {
+	return s.reactions
 }
 
-func (s *DuckService) GetUserDucksList(userId uint) (*[]model.Duck, error) {
+func (s *DuckService) GetDucksList(filter DuckListFilter) (*DuckListPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDucksPageSize
+	}
+	if limit > maxDucksPageSize {
+		limit = maxDucksPageSize
+	}
+
+	query := s.db.Preload("Owner")
+
+	if filter.Cursor != "" {
+		cursor, err := utils.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	if filter.Skin != "" {
+		query = query.Where("appearance->>'skin' = ?", string(filter.Skin))
+	}
+
+	if filter.Accessory != "" {
+		accessories, err := json.Marshal([]types.AccessoryType{filter.Accessory})
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("appearance->'accessories' @> ?::jsonb", string(accessories))
+	}
+
+	if filter.OwnerID != 0 {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at > ?", *filter.CreatedAfter)
+	}
+
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+
 	ducks := []model.Duck{}
-	if err := s.db.Preload("Owner").Order("created_at DESC").Where("owner_id = ?", userId).Find(&ducks).Error; err != nil {
+	if err := query.Order("created_at DESC").Order("id DESC").Limit(limit + 1).Find(&ducks).Error; err != nil {
 		return nil, err
 	}
 
-	return &ducks, nil
-}
+	page := &DuckListPage{Items: ducks}
 
-func (s *DuckService) GetDucksLeaderboard() (*[]model.Duck, error) {
-	ducks := []model.Duck{}
+	if len(ducks) > limit {
+		page.Items = ducks[:limit]
+		page.HasMore = true
 
-	if err := s.db.Preload("Owner").Where("rank > ?", 0).Order("rank ASC").Limit(100).Find(&ducks).Error; err != nil {
-		return nil, err
+		last := page.Items[limit-1]
+		page.NextCursor = utils.EncodeCursor(utils.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
 	}
 
-	return &ducks, nil
+	return page, nil
+}
+
+func (s *DuckService) GetUserDucksList(userId uint, filter DuckListFilter) (*DuckListPage, error) {
+	filter.OwnerID = userId
+
+	return s.GetDucksList(filter)
 }
 
 func (s *DuckService) RemoveDuck(userId uint, duckId uint) (bool, error) {
@@ -203,25 +602,203 @@ func (s *DuckService) RemoveDuck(userId uint, duckId uint) (bool, error) {
 		return false, err
 	}
 
-	if err := s.db.Delete(&duck).Error; err != nil {
+	// A removed duck gives up its rank right away; it is ranked again on
+	// the next leaderboard run if it is restored.
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&duck).UpdateColumn("rank", 0).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&duck).Error
+	})
+	if err != nil {
 		return false, err
 	}
 
+	if s.broadcaster != nil {
+		notification := websocket.NewNotification(websocket.NotificationTypeDuckRemoved, websocket.DuckRemovedData{
+			DuckID:  duck.ID,
+			OwnerID: duck.OwnerID,
+		}, websocket.TopicDucks, websocket.DuckTopic(duck.ID), websocket.UserTopic(duck.OwnerID))
+		s.broadcaster.Broadcast(notification)
+	}
+
 	return true, nil
 }
 
-func updateReactionCounts(duck *model.Duck, reaction model.ReactionType, delta int64) {
-	switch reaction {
-	case model.ReactionLike:
-		duck.LikesCount = clampNonNegative(duck.LikesCount + delta)
-	case model.ReactionDislike:
-		duck.DislikesCount = clampNonNegative(duck.DislikesCount + delta)
+func (s *DuckService) MoveDuck(userId uint, duckId uint, position placement.Point) (*model.Duck, error) {
+	var duck model.Duck
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Owner").Where("id = ? AND owner_id = ?", duckId, userId).First(&duck).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDuckNotFound
+			}
+			return err
+		}
+
+		placed, err := s.placeDuck(tx, &position, duck.ID)
+		if err != nil {
+			return err
+		}
+
+		duck.X = placed.X
+		duck.Y = placed.Y
+
+		return tx.Model(&duck).Select("x", "y").Updates(&duck).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.broadcaster != nil {
+		notification := websocket.NewNotification(websocket.NotificationTypeDuckMoved, websocket.DuckMovedData{
+			DuckID: duck.ID,
+			X:      duck.X,
+			Y:      duck.Y,
+		}, websocket.TopicDucks, websocket.DuckTopic(duck.ID))
+		s.broadcaster.Broadcast(notification)
+	}
+
+	return &duck, nil
+}
+
+// grantUnlocks is best effort: a missed unlock is granted again on the
+// user's next duck or like.
+func (s *DuckService) grantUnlocks(userID uint) {
+	if err := s.cosmeticService.GrantUnlocks(userID); err != nil {
+		slog.Default().Error("failed to grant cosmetic unlocks", "user_id", userID, "error", err)
 	}
 }
 
-func clampNonNegative(value int64) int64 {
-	if value < 0 {
-		return 0
+// GetUserTrash returns the user's removed ducks that can still be restored,
+// most recently removed first.
+func (s *DuckService) GetUserTrash(userId uint) ([]model.Duck, error) {
+	ducks := []model.Duck{}
+
+	if err := s.db.Unscoped().
+		Preload("Owner").
+		Where("owner_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", userId, time.Now().Add(-model.RemovedDuckRetention)).
+		Order("deleted_at DESC").
+		Find(&ducks).Error; err != nil {
+		return nil, err
 	}
-	return value
+
+	return ducks, nil
+}
+
+// RestoreDuck brings a removed duck back from the trash. If another duck
+// took its spot in the meantime, it is placed somewhere free instead.
+func (s *DuckService) RestoreDuck(userId uint, duckId uint) (*model.Duck, error) {
+	var duck model.Duck
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND owner_id = ? AND deleted_at IS NOT NULL", duckId, userId).
+			First(&duck).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDuckNotFound
+			}
+			return err
+		}
+
+		if duck.DeletedAt.Time.Before(time.Now().Add(-model.RemovedDuckRetention)) {
+			return ErrDuckRestoreExpired
+		}
+
+		position, err := s.placeDuck(tx, &placement.Point{X: duck.X, Y: duck.Y}, duck.ID)
+		if errors.Is(err, placement.ErrTooClose) {
+			position, err = s.placeDuck(tx, nil, duck.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&duck).UpdateColumns(map[string]interface{}{
+			"deleted_at": nil,
+			"x":          position.X,
+			"y":          position.Y,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Owner").First(&duck, duck.ID).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.broadcaster != nil {
+		notification := websocket.NewNotification(websocket.NotificationTypeDuckRestored, duck_dto.NewDuckResponse(duck), websocket.TopicDucks, websocket.DuckTopic(duck.ID), websocket.UserTopic(duck.OwnerID))
+		s.broadcaster.Broadcast(notification)
+	}
+
+	return &duck, nil
+}
+
+type duckImageURLs struct {
+	Original  string
+	Medium    string
+	Thumbnail string
+}
+
+func (s *DuckService) uploadDuckImages(name string, processedImage *imaging.ProcessedImage) (*duckImageURLs, error) {
+	ctx := context.Background()
+	prefix := storage.NewDuckImagePrefix(name)
+
+	images := &duckImageURLs{}
+	variants := []struct {
+		key     string
+		content []byte
+		url     *string
+	}{
+		{key: prefix + ".png", content: processedImage.Original, url: &images.Original},
+		{key: prefix + "_medium.png", content: processedImage.Medium, url: &images.Medium},
+		{key: prefix + "_thumb.png", content: processedImage.Thumbnail, url: &images.Thumbnail},
+	}
+
+	for _, variant := range variants {
+		url, err := s.storage.UploadFile(ctx, variant.key, variant.content, imaging.ContentTypePNG)
+		if err != nil {
+			s.deleteStoredImages(images.Original, images.Medium, images.Thumbnail)
+			return nil, err
+		}
+
+		*variant.url = url
+	}
+
+	return images, nil
+}
+
+// deleteStoredImages is best effort: anything it fails to remove is picked
+// up later by the orphaned image sweeper.
+func (s *DuckService) deleteStoredImages(urls ...string) {
+	ctx := context.Background()
+
+	for _, url := range urls {
+		key, ok := storage.KeyFromURL(s.storage, url)
+		if !ok {
+			continue
+		}
+
+		if err := s.storage.DeleteFile(ctx, key); err != nil {
+			slog.Default().Error("failed to delete duck image", "key", key, "error", err)
+		}
+	}
+}
+
+func (s *DuckService) broadcastReactionCounts(duck *model.Duck) {
+	counts := make(map[string]int64, len(duck.ReactionCounts))
+	for reaction, count := range duck.ReactionCounts {
+		counts[string(reaction)] = count
+	}
+
+	notification := websocket.NewNotification(websocket.NotificationTypeDuckReactionChanged, websocket.DuckReactionChangedData{
+		DuckID:         duck.ID,
+		ReactionCounts: counts,
+	}, websocket.TopicDucks, websocket.DuckTopic(duck.ID))
+	s.broadcaster.Broadcast(notification)
 }
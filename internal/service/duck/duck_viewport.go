@@ -0,0 +1,137 @@
+package duckService
+
+import (
+	"errors"
+	"math"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/placement"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultViewportLimit = 200
+	maxViewportLimit     = 500
+	// densityGridSize is how many cells per side a crowded viewport is
+	// summarized into.
+	densityGridSize = 16
+)
+
+var ErrInvalidViewport = errors.New("invalid viewport: min must not exceed max")
+
+type Viewport struct {
+	MinX  float64
+	MinY  float64
+	MaxX  float64
+	MaxY  float64
+	Limit int
+}
+
+// DensityCell counts the ducks inside one cell of a crowded viewport.
+type DensityCell struct {
+	MinX  float64
+	MinY  float64
+	MaxX  float64
+	MaxY  float64
+	Count int64
+}
+
+// ViewportPage lists the ducks in a viewport, or only their density when
+// there are more than the limit.
+type ViewportPage struct {
+	Items     []model.Duck
+	Total     int64
+	Truncated bool
+	Density   []DensityCell
+}
+
+func (s *DuckService) GetDucksInViewport(viewport Viewport) (*ViewportPage, error) {
+	if viewport.MinX > viewport.MaxX || viewport.MinY > viewport.MaxY {
+		return nil, ErrInvalidViewport
+	}
+
+	limit := viewport.Limit
+	if limit <= 0 {
+		limit = defaultViewportLimit
+	}
+	if limit > maxViewportLimit {
+		limit = maxViewportLimit
+	}
+
+	viewport.MinX = math.Max(viewport.MinX, 0)
+	viewport.MinY = math.Max(viewport.MinY, 0)
+	viewport.MaxX = math.Min(viewport.MaxX, placement.CanvasWidth)
+	viewport.MaxY = math.Min(viewport.MaxY, placement.CanvasHeight)
+
+	page := &ViewportPage{Items: []model.Duck{}}
+	if viewport.MinX > viewport.MaxX || viewport.MinY > viewport.MaxY {
+		return page, nil
+	}
+
+	inViewport := s.db.Model(&model.Duck{}).
+		Where("x BETWEEN ? AND ? AND y BETWEEN ? AND ?", viewport.MinX, viewport.MaxX, viewport.MinY, viewport.MaxY)
+
+	if err := inViewport.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	if page.Total <= int64(limit) {
+		if err := inViewport.Session(&gorm.Session{}).Preload("Owner").Order("id").Find(&page.Items).Error; err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+
+	density, err := s.viewportDensity(viewport)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Truncated = true
+	page.Density = density
+
+	return page, nil
+}
+
+// viewportDensity buckets the ducks in viewport into a grid of square cells
+// so crowded regions can be drawn as a heatmap instead.
+func (s *DuckService) viewportDensity(viewport Viewport) ([]DensityCell, error) {
+	cellSize := math.Max(viewport.MaxX-viewport.MinX, viewport.MaxY-viewport.MinY) / densityGridSize
+	if cellSize <= 0 {
+		cellSize = placement.MinSpacing
+	}
+
+	var buckets []struct {
+		CellCol int64
+		CellRow int64
+		Count   int64
+	}
+
+	// Ducks on the max edge would start a cell of their own; LEAST folds
+	// them into the last one.
+	err := s.db.Model(&model.Duck{}).
+		Select("LEAST(FLOOR((x - ?) / ?), ?) AS cell_col, LEAST(FLOOR((y - ?) / ?), ?) AS cell_row, COUNT(*) AS count",
+			viewport.MinX, cellSize, densityGridSize-1, viewport.MinY, cellSize, densityGridSize-1).
+		Where("x BETWEEN ? AND ? AND y BETWEEN ? AND ?", viewport.MinX, viewport.MaxX, viewport.MinY, viewport.MaxY).
+		Group("cell_col, cell_row").
+		Order("cell_row, cell_col").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make([]DensityCell, 0, len(buckets))
+	for _, bucket := range buckets {
+		minX := viewport.MinX + float64(bucket.CellCol)*cellSize
+		minY := viewport.MinY + float64(bucket.CellRow)*cellSize
+		cells = append(cells, DensityCell{
+			MinX:  minX,
+			MinY:  minY,
+			MaxX:  math.Min(minX+cellSize, viewport.MaxX),
+			MaxY:  math.Min(minY+cellSize, viewport.MaxY),
+			Count: bucket.Count,
+		})
+	}
+
+	return cells, nil
+}
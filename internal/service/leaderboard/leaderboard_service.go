@@ -0,0 +1,325 @@
+package leaderboardService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omidnikrah/duckparty-backend/internal/model"
+	"github.com/omidnikrah/duckparty-backend/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// windowCacheTTL bounds how stale the live day, week and month rankings
+// can be. Ranking a window aggregates every reaction left in it, so it is
+// not repeated for every request.
+const windowCacheTTL = 30 * time.Second
+
+var (
+	ErrInvalidPeriod    = errors.New("invalid leaderboard period")
+	ErrSnapshotNotFound = errors.New("no leaderboard snapshot for this period")
+)
+
+type LeaderboardService struct {
+	db        *gorm.DB
+	reactions *types.ReactionSet
+
+	mu      sync.Mutex
+	windows map[model.LeaderboardPeriod]cachedWindow
+}
+
+// cachedWindow is the full ranking of the period starting at start.
+type cachedWindow struct {
+	start    time.Time
+	ranked   []rankedDuck
+	loadedAt time.Time
+}
+
+func NewService(db *gorm.DB, reactions *types.ReactionSet) *LeaderboardService {
+	return &LeaderboardService{
+		db:        db,
+		reactions: reactions,
+		windows:   make(map[model.LeaderboardPeriod]cachedWindow),
+	}
+}
+
+type LeaderboardEntry struct {
+	Rank uint
+	// ReactionCounts are the reactions the duck received during the period.
+	ReactionCounts model.ReactionCounts
+	DuckName       string
+	DuckImage      string
+	// Duck is nil for past winners that have since been deleted.
+	Duck *model.Duck
+}
+
+type Leaderboard struct {
+	Period model.LeaderboardPeriod
+	// StartsAt and EndsAt are nil for the all-time leaderboard.
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Entries  []LeaderboardEntry
+}
+
+// rankedDuck is a row of the windowed ranking query.
+type rankedDuck struct {
+	DuckID         uint
+	Rank           uint
+	ReactionCounts model.ReactionCounts
+}
+
+// GetLeaderboard returns the top limit ducks of the current period. The
+// all-time leaderboard uses the ranks kept by the leaderboard job; shorter
+// periods are ranked live from the reactions left since the period began.
+func (s *LeaderboardService) GetLeaderboard(period model.LeaderboardPeriod, limit int) (*Leaderboard, error) {
+	if !period.IsValid() {
+		return nil, ErrInvalidPeriod
+	}
+
+	limit = clampLimit(limit)
+
+	start, end, windowed := period.Bounds(time.Now())
+	if !windowed {
+		return s.getAllTimeLeaderboard(limit)
+	}
+
+	ranked, err := s.cachedRanking(period, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	entries, err := s.loadEntries(ranked)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Leaderboard{Period: period, StartsAt: &start, EndsAt: &end, Entries: entries}, nil
+}
+
+// GetSnapshot returns the top limit ducks of the finished period containing
+// at. Without at, it returns the last finished period.
+func (s *LeaderboardService) GetSnapshot(period model.LeaderboardPeriod, at *time.Time, limit int) (*Leaderboard, error) {
+	var (
+		start, end time.Time
+		ok         bool
+	)
+
+	if at != nil {
+		start, end, ok = period.Bounds(*at)
+	} else {
+		start, end, ok = period.Previous(time.Now())
+	}
+	if !ok {
+		return nil, ErrInvalidPeriod
+	}
+
+	snapshot := []model.LeaderboardSnapshotEntry{}
+	if err := s.db.
+		Preload("Duck", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Duck.Owner").
+		Where("period = ? AND starts_at = ?", period, start).
+		Order("rank ASC").
+		Limit(clampLimit(limit)).
+		Find(&snapshot).Error; err != nil {
+		return nil, err
+	}
+
+	if len(snapshot) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+
+	entries := make([]LeaderboardEntry, len(snapshot))
+	for i, entry := range snapshot {
+		entries[i] = LeaderboardEntry{
+			Rank:           entry.Rank,
+			ReactionCounts: entry.ReactionCounts,
+			DuckName:       entry.DuckName,
+			DuckImage:      entry.DuckImage,
+			Duck:           entry.Duck,
+		}
+	}
+
+	return &Leaderboard{Period: period, StartsAt: &start, EndsAt: &end, Entries: entries}, nil
+}
+
+// cachedRanking returns the full ranking of the current period, reusing it
+// for windowCacheTTL.
+func (s *LeaderboardService) cachedRanking(period model.LeaderboardPeriod, start time.Time, end time.Time) ([]rankedDuck, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.windows[period]; ok && cached.start.Equal(start) && time.Since(cached.loadedAt) < windowCacheTTL {
+		return cached.ranked, nil
+	}
+
+	var ranked []rankedDuck
+	if err := s.rankWindow(start, end, model.LeaderboardSize).Scan(&ranked).Error; err != nil {
+		return nil, err
+	}
+
+	s.windows[period] = cachedWindow{start: start, ranked: ranked, loadedAt: time.Now()}
+
+	return ranked, nil
+}
+
+// SnapshotFinishedPeriods saves the winners of every finished period since
+// the last saved one, so periods missed while the job was not running are
+// filled in too. Without any saved period it starts from the period that
+// ended most recently. Saved periods are recorded even when nobody received
+// reactions in them, and are left alone, so it is safe to run at any time.
+func (s *LeaderboardService) SnapshotFinishedPeriods(ctx context.Context) (int64, error) {
+	var saved int64
+
+	for _, period := range model.SnapshotPeriods {
+		lastStart, _, _ := period.Previous(time.Now())
+
+		var latest sql.NullTime
+		if err := s.db.WithContext(ctx).
+			Model(&model.LeaderboardSnapshotPeriod{}).
+			Where("period = ?", period).
+			Select("MAX(starts_at)").
+			Row().
+			Scan(&latest); err != nil {
+			return saved, fmt.Errorf("find latest %s snapshot: %w", period, err)
+		}
+
+		start := lastStart
+		if latest.Valid {
+			_, start, _ = period.Bounds(latest.Time)
+		}
+
+		for !start.After(lastStart) {
+			if err := ctx.Err(); err != nil {
+				return saved, err
+			}
+
+			_, end, _ := period.Bounds(start)
+
+			var inserted int64
+			err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				result := tx.Exec(`INSERT INTO leaderboard_snapshot_entries
+						(created_at, period, starts_at, ends_at, rank, duck_id, duck_name, duck_image, reaction_counts)
+					SELECT NOW(), ?, ?, ?, ranked.rank, ranked.duck_id, ducks.name, ducks.image, ranked.reaction_counts
+					FROM (?) AS ranked
+					JOIN ducks ON ducks.id = ranked.duck_id
+					ON CONFLICT DO NOTHING`, period, start, end, s.rankWindow(start, end, model.LeaderboardSize))
+				if result.Error != nil {
+					return result.Error
+				}
+				inserted = result.RowsAffected
+
+				return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.LeaderboardSnapshotPeriod{
+					Period:   period,
+					StartsAt: start,
+					EndsAt:   end,
+				}).Error
+			})
+			if err != nil {
+				return saved, fmt.Errorf("save %s snapshot of %s: %w", period, start.Format(time.DateOnly), err)
+			}
+
+			saved += inserted
+			start = end
+		}
+	}
+
+	return saved, nil
+}
+
+// rankWindow ranks the ducks that received reactions between start and end
+// by the reactions left in that window, the same way the all-time ranking
+// does.
+func (s *LeaderboardService) rankWindow(start time.Time, end time.Time, limit int) *gorm.DB {
+	return s.db.Raw(`SELECT totals.duck_id, totals.reaction_counts,
+			ROW_NUMBER() OVER (ORDER BY ? DESC, ? ASC, totals.duck_id ASC) AS rank
+		FROM (
+			SELECT counts.duck_id, jsonb_object_agg(counts.reaction, counts.count) AS reaction_counts
+			FROM (
+				SELECT duck_reactions.duck_id, duck_reactions.reaction, COUNT(*) AS count
+				FROM duck_reactions
+				JOIN ducks ON ducks.id = duck_reactions.duck_id AND ducks.deleted_at IS NULL
+				WHERE duck_reactions.created_at >= ? AND duck_reactions.created_at < ?
+				GROUP BY duck_reactions.duck_id, duck_reactions.reaction
+			) AS counts
+			GROUP BY counts.duck_id
+		) AS totals
+		ORDER BY rank
+		LIMIT ?`,
+		s.reactions.PositiveCount("totals.reaction_counts"),
+		s.reactions.NegativeCount("totals.reaction_counts"),
+		start, end, limit,
+	)
+}
+
+func (s *LeaderboardService) getAllTimeLeaderboard(limit int) (*Leaderboard, error) {
+	ducks := []model.Duck{}
+	if err := s.db.Preload("Owner").Where("rank > ?", 0).Order("rank ASC").Limit(limit).Find(&ducks).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, len(ducks))
+	for i := range ducks {
+		entries[i] = newLeaderboardEntry(ducks[i].Rank, ducks[i].ReactionCounts, &ducks[i])
+	}
+
+	return &Leaderboard{Period: model.PeriodAll, Entries: entries}, nil
+}
+
+// loadEntries fetches the ranked ducks, keeping the ranking order.
+func (s *LeaderboardService) loadEntries(ranked []rankedDuck) ([]LeaderboardEntry, error) {
+	ids := make([]uint, len(ranked))
+	for i, duck := range ranked {
+		ids[i] = duck.DuckID
+	}
+
+	ducks := []model.Duck{}
+	if len(ids) > 0 {
+		if err := s.db.Preload("Owner").Where("id IN ?", ids).Find(&ducks).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	byID := make(map[uint]*model.Duck, len(ducks))
+	for i := range ducks {
+		byID[ducks[i].ID] = &ducks[i]
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(ranked))
+	for _, entry := range ranked {
+		duck, ok := byID[entry.DuckID]
+		if !ok {
+			// Removed since the ranking was read.
+			continue
+		}
+		entries = append(entries, newLeaderboardEntry(entry.Rank, entry.ReactionCounts, duck))
+	}
+
+	return entries, nil
+}
+
+func newLeaderboardEntry(rank uint, counts model.ReactionCounts, duck *model.Duck) LeaderboardEntry {
+	return LeaderboardEntry{
+		Rank:           rank,
+		ReactionCounts: counts,
+		DuckName:       duck.Name,
+		DuckImage:      duck.Image,
+		Duck:           duck,
+	}
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 || limit > model.LeaderboardSize {
+		return model.LeaderboardSize
+	}
+
+	return limit
+}
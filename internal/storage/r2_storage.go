@@ -3,11 +3,12 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/google/uuid"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	appconfig "github.com/omidnikrah/duckparty-backend/internal/config"
 )
 
@@ -35,27 +36,80 @@ func NewR2Storage(appConfig *appconfig.Config) (*R2Storage, error) {
 	}, nil
 }
 
-func (s *R2Storage) UploadFile(fileContent []byte, name string) (string, error) {
-	uniqueID := generateUniqueID()
-	filename := fmt.Sprintf("duck_%s_%s.png", name, uniqueID)
-	key := fmt.Sprintf("ducks/%s", filename)
-
-	_, err := s.client.PutObject(context.TODO(), &s3.PutObjectInput{
+func (s *R2Storage) UploadFile(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:        aws.String(s.cfg.R2Bucket),
 		Key:           aws.String(key),
-		Body:          bytes.NewReader(fileContent),
-		ContentType:   aws.String("image/png"),
-		ContentLength: aws.Int64(int64(len(fileContent))),
+		Body:          bytes.NewReader(content),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(content))),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file to R2: %w", err)
 	}
 
-	imageURL := fmt.Sprintf("%s/%s", s.cfg.R2BaseURL, key)
-	return imageURL, nil
+	return s.PublicURL(key), nil
+}
+
+func (s *R2Storage) DeleteFile(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.cfg.R2Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file from R2: %w", err)
+	}
+
+	return nil
+}
+
+func (s *R2Storage) HeadFile(ctx context.Context, key string) (*FileInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.cfg.R2Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *s3types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to head file in R2: %w", err)
+	}
+
+	return &FileInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+func (s *R2Storage) ListFiles(ctx context.Context, prefix string) ([]FileInfo, error) {
+	files := []FileInfo{}
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.cfg.R2Bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in R2: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			files = append(files, FileInfo{
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+
+	return files, nil
 }
 
-func generateUniqueID() string {
-	id := uuid.New()
-	return id.String()[:8]
+func (s *R2Storage) PublicURL(key string) string {
+	return fmt.Sprintf("%s/%s", s.cfg.R2BaseURL, escapeKey(key))
 }
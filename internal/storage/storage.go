@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	appconfig "github.com/omidnikrah/duckparty-backend/internal/config"
+)
+
+const (
+	DriverR2    = "r2"
+	DriverLocal = "local"
+)
+
+// DuckImageKeyPrefix is shared by every key built with NewDuckImagePrefix.
+const DuckImageKeyPrefix = "ducks/duck_"
+
+const maxSlugLength = 32
+
+var ErrFileNotFound = errors.New("file not found")
+
+type FileInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Storage is the object store used for duck images. Keys are slash-separated
+// paths such as "ducks/duck_name_1a2b3c4d_thumb.png".
+type Storage interface {
+	UploadFile(ctx context.Context, key string, content []byte, contentType string) (string, error)
+	DeleteFile(ctx context.Context, key string) error
+	HeadFile(ctx context.Context, key string) (*FileInfo, error)
+	ListFiles(ctx context.Context, prefix string) ([]FileInfo, error)
+	PublicURL(key string) string
+}
+
+func New(appConfig *appconfig.Config) (Storage, error) {
+	switch appConfig.StorageDriver {
+	case DriverR2:
+		return NewR2Storage(appConfig)
+	case DriverLocal:
+		return NewLocalStorage(appConfig.StorageLocalDir, appConfig.StoragePublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", appConfig.StorageDriver)
+	}
+}
+
+// NewDuckImagePrefix returns a fresh key prefix under ducks/. Callers append
+// a variant suffix and extension to build the final keys. Only a slug of the
+// name goes into the key, so keys never need escaping.
+func NewDuckImagePrefix(name string) string {
+	if slug := slugify(name); slug != "" {
+		return fmt.Sprintf("%s%s_%s", DuckImageKeyPrefix, slug, generateUniqueID())
+	}
+
+	return DuckImageKeyPrefix + generateUniqueID()
+}
+
+// escapeKey path-escapes each segment of key for use in a public URL.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// KeyFromURL reverses PublicURL. Only the path is compared, so URLs saved
+// before the public host changed still map to their key. It reports false
+// for URLs whose path is outside the storage's base path.
+func KeyFromURL(s Storage, fileURL string) (string, bool) {
+	basePath := strings.TrimSuffix(rawURLPath(s.PublicURL("")), "/") + "/"
+	filePath := rawURLPath(fileURL)
+	if !strings.HasPrefix(filePath, basePath) || len(filePath) == len(basePath) {
+		return "", false
+	}
+
+	escaped := strings.TrimPrefix(filePath, basePath)
+	if key, err := url.PathUnescape(escaped); err == nil && escapeKey(key) == escaped {
+		return key, true
+	}
+
+	// URLs saved before keys were escaped hold the key verbatim, including
+	// any '#', '?' or '%' from the duck name.
+	return escaped, true
+}
+
+// rawURLPath returns everything after the host of rawURL, as stored. It does
+// not parse the URL, so a '#' or '?' that was never escaped stays part of
+// the path.
+func rawURLPath(rawURL string) string {
+	scheme := strings.Index(rawURL, "://")
+	if scheme < 0 {
+		return rawURL
+	}
+
+	rest := rawURL[scheme+len("://"):]
+	host := strings.IndexByte(rest, '/')
+	if host < 0 {
+		return "/"
+	}
+
+	return rest[host:]
+}
+
+// slugify lowercases name and keeps only ASCII letters and digits, joining
+// runs of anything else with a single dash.
+func slugify(name string) string {
+	var slug strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+
+		if slug.Len() >= maxSlugLength {
+			break
+		}
+	}
+
+	return slug.String()
+}
+
+func generateUniqueID() string {
+	id := uuid.New()
+	return id.String()[:8]
+}
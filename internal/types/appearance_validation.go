@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type catalogContextKey struct{}
+
+var appearanceValidator = newAppearanceValidator()
+
+func newAppearanceValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterValidationCtx("known_skin", func(ctx context.Context, fl validator.FieldLevel) bool {
+		return catalogFrom(ctx).HasSkin(SkinType(fl.Field().String()))
+	})
+
+	v.RegisterValidationCtx("known_accessory", func(ctx context.Context, fl validator.FieldLevel) bool {
+		_, ok := catalogFrom(ctx).AccessorySlot(AccessoryType(fl.Field().String()))
+		return ok
+	})
+
+	v.RegisterStructValidationCtx(validateAccessorySlots, DuckAppearance{})
+
+	return v
+}
+
+func catalogFrom(ctx context.Context) AppearanceCatalog {
+	if catalog, ok := ctx.Value(catalogContextKey{}).(AppearanceCatalog); ok {
+		return catalog
+	}
+
+	return DefaultCatalog
+}
+
+// validateAccessorySlots caps the number of accessories and rejects
+// appearances wearing two in the same slot, like two hats.
+func validateAccessorySlots(ctx context.Context, sl validator.StructLevel) {
+	appearance := sl.Current().Interface().(DuckAppearance)
+	catalog := catalogFrom(ctx)
+
+	if len(appearance.Accessories) > MaxAccessories {
+		sl.ReportError(appearance.Accessories, "accessories", "Accessories", "max", strconv.Itoa(MaxAccessories))
+		return
+	}
+
+	taken := make(map[CosmeticSlot]AccessoryType, len(appearance.Accessories))
+	for _, accessory := range appearance.Accessories {
+		slot, ok := catalog.AccessorySlot(accessory)
+		if !ok {
+			continue
+		}
+
+		if other, ok := taken[slot]; ok && other != accessory {
+			sl.ReportError(appearance.Accessories, "accessories", "Accessories", "slot_conflict", string(slot))
+			return
+		}
+		taken[slot] = accessory
+	}
+}
+
+// ValidateAppearance checks an appearance against catalog. Failures are
+// returned as validator.ValidationErrors so they can be reported with
+// utils.FormatValidationError.
+func ValidateAppearance(ctx context.Context, appearance DuckAppearance, catalog AppearanceCatalog) error {
+	return appearanceValidator.StructCtx(context.WithValue(ctx, catalogContextKey{}, catalog), appearance)
+}
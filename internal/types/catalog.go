@@ -0,0 +1,86 @@
+package types
+
+// CosmeticSlot is where a cosmetic sits on the duck. A duck has exactly one
+// skin and can wear at most one accessory per other slot.
+type CosmeticSlot string // @name CosmeticSlot
+
+const (
+	SlotSkin CosmeticSlot = "skin"
+	SlotHead CosmeticSlot = "head"
+	SlotBack CosmeticSlot = "back"
+)
+
+func (s CosmeticSlot) IsValid() bool {
+	switch s {
+	case SlotSkin, SlotHead, SlotBack:
+		return true
+	}
+
+	return false
+}
+
+// MaxAccessories is the most accessories a duck can wear at once.
+const MaxAccessories = 3
+
+// AppearanceCatalog lists the skins and accessories a duck may use.
+type AppearanceCatalog interface {
+	HasSkin(skin SkinType) bool
+	// AccessorySlot reports the slot of an accessory, or false when the
+	// accessory is not available.
+	AccessorySlot(accessory AccessoryType) (CosmeticSlot, bool)
+}
+
+type staticCatalog map[string]CosmeticSlot
+
+// NewCatalog builds a catalog from cosmetic IDs and their slots.
+func NewCatalog(slots map[string]CosmeticSlot) AppearanceCatalog {
+	return staticCatalog(slots)
+}
+
+// DefaultCatalog is the built-in set of skins and accessories, used to seed
+// the cosmetics table.
+var DefaultCatalog = NewCatalog(map[string]CosmeticSlot{
+	string(SkinGiraffe):           SlotSkin,
+	string(SkinLGBT):              SlotSkin,
+	string(SkinSuperman):          SlotSkin,
+	string(AccessoryFlowerCrown):  SlotHead,
+	string(AccessoryKingCrown):    SlotHead,
+	string(AccessoryVespaHelmet):  SlotHead,
+	string(AccessorySupermanCape): SlotBack,
+})
+
+func (c staticCatalog) HasSkin(skin SkinType) bool {
+	return c[string(skin)] == SlotSkin
+}
+
+func (c staticCatalog) AccessorySlot(accessory AccessoryType) (CosmeticSlot, bool) {
+	slot, ok := c[string(accessory)]
+	if !ok || slot == SlotSkin {
+		return "", false
+	}
+
+	return slot, true
+}
+
+type extendedCatalog struct {
+	AppearanceCatalog
+	extra staticCatalog
+}
+
+// ExtendCatalog returns catalog with extra cosmetics added to it, given as
+// cosmetic IDs and their slots.
+func ExtendCatalog(catalog AppearanceCatalog, extra map[string]CosmeticSlot) AppearanceCatalog {
+	return extendedCatalog{AppearanceCatalog: catalog, extra: staticCatalog(extra)}
+}
+
+func (c extendedCatalog) HasSkin(skin SkinType) bool {
+	return c.extra.HasSkin(skin) || c.AppearanceCatalog.HasSkin(skin)
+}
+
+func (c extendedCatalog) AccessorySlot(accessory AccessoryType) (CosmeticSlot, bool) {
+	if slot, ok := c.extra.AccessorySlot(accessory); ok {
+		return slot, true
+	}
+
+	return c.AppearanceCatalog.AccessorySlot(accessory)
+}
@@ -17,6 +17,6 @@ const (
 )
 
 type DuckAppearance struct {
-	Skin        SkinType        `json:"skin"`
-	Accessories []AccessoryType `json:"accessories"`
+	Skin        SkinType        `json:"skin" validate:"required,known_skin"`
+	Accessories []AccessoryType `json:"accessories" validate:"unique,dive,known_accessory"`
 } // @name DuckAppearance
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+type ReactionType string // @name ReactionType
+
+const (
+	ReactionLike    ReactionType = "like"
+	ReactionDislike ReactionType = "dislike"
+)
+
+var (
+	DefaultReactionTypes         = []string{"like", "dislike", "quack", "heart", "fire", "laugh"}
+	DefaultNegativeReactionTypes = []string{"dislike"}
+)
+
+var ErrInvalidReactionSet = errors.New("invalid reaction set")
+
+var reactionTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// ReactionSet is the configured set of reactions users can leave on ducks.
+// Negative reactions, such as dislike, count against a duck in the ranking.
+type ReactionSet struct {
+	types    []ReactionType
+	negative map[ReactionType]bool
+}
+
+func NewReactionSet(types []string, negative []string) (*ReactionSet, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%w: at least one reaction type is required", ErrInvalidReactionSet)
+	}
+
+	set := &ReactionSet{negative: make(map[ReactionType]bool, len(negative))}
+	seen := make(map[ReactionType]bool, len(types))
+
+	for _, value := range types {
+		if !reactionTypePattern.MatchString(value) {
+			return nil, fmt.Errorf("%w: %q is not a valid reaction type", ErrInvalidReactionSet, value)
+		}
+
+		reaction := ReactionType(value)
+		if seen[reaction] {
+			continue
+		}
+
+		seen[reaction] = true
+		set.types = append(set.types, reaction)
+	}
+
+	for _, value := range negative {
+		reaction := ReactionType(value)
+		if !seen[reaction] {
+			return nil, fmt.Errorf("%w: negative reaction %q is not a reaction type", ErrInvalidReactionSet, value)
+		}
+
+		set.negative[reaction] = true
+	}
+
+	return set, nil
+}
+
+// Types returns the reaction types in their configured order.
+func (s *ReactionSet) Types() []ReactionType {
+	return append([]ReactionType(nil), s.types...)
+}
+
+func (s *ReactionSet) IsValid(reaction ReactionType) bool {
+	for _, known := range s.types {
+		if known == reaction {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *ReactionSet) IsNegative(reaction ReactionType) bool {
+	return s.negative[reaction]
+}
+
+// PositiveCount is an SQL expression summing the positive reactions stored
+// in column, a jsonb ReactionCounts column such as ducks.reaction_counts.
+func (s *ReactionSet) PositiveCount(column string) clause.Expr {
+	return clause.Expr{
+		SQL:  "(SELECT COALESCE(SUM(value::bigint), 0) FROM jsonb_each_text(" + column + ") WHERE key <> ALL(string_to_array(?, ',')))",
+		Vars: []interface{}{s.negativeList()},
+	}
+}
+
+// NegativeCount is the PositiveCount counterpart for negative reactions.
+func (s *ReactionSet) NegativeCount(column string) clause.Expr {
+	return clause.Expr{
+		SQL:  "(SELECT COALESCE(SUM(value::bigint), 0) FROM jsonb_each_text(" + column + ") WHERE key = ANY(string_to_array(?, ',')))",
+		Vars: []interface{}{s.negativeList()},
+	}
+}
+
+// negativeList joins the negative reactions with commas, which the reaction
+// type pattern keeps out of the names themselves.
+func (s *ReactionSet) negativeList() string {
+	names := make([]string, 0, len(s.negative))
+	for _, reaction := range s.types {
+		if s.negative[reaction] {
+			names = append(names, string(reaction))
+		}
+	}
+
+	return strings.Join(names, ",")
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -21,6 +22,9 @@ func FormatValidationError(err error) []string {
 	for _, fieldError := range validationErrors {
 		fieldName := getFieldName(fieldError.Field())
 		message := getValidationMessage(fieldName, fieldError.Tag(), fieldError.Param())
+		if fieldError.Kind() == reflect.Slice {
+			message = getSliceValidationMessage(fieldName, fieldError.Tag(), fieldError.Param())
+		}
 		messages = append(messages, message)
 	}
 
@@ -52,7 +56,26 @@ func getValidationMessage(fieldName, tag, param string) string {
 		return fmt.Sprintf("%s must contain only letters", fieldName)
 	case "alphanum":
 		return fmt.Sprintf("%s must contain only letters and numbers", fieldName)
+	case "known_skin":
+		return fmt.Sprintf("%s is not an available skin", fieldName)
+	case "known_accessory":
+		return fmt.Sprintf("%s is not an available accessory", fieldName)
 	default:
 		return fmt.Sprintf("%s is invalid", fieldName)
 	}
 }
+
+func getSliceValidationMessage(fieldName, tag, param string) string {
+	switch tag {
+	case "min":
+		return fmt.Sprintf("%s must have at least %s items", fieldName, param)
+	case "max":
+		return fmt.Sprintf("%s must have at most %s items", fieldName, param)
+	case "unique":
+		return fmt.Sprintf("%s must not contain duplicates", fieldName)
+	case "slot_conflict":
+		return fmt.Sprintf("%s can only have one item in the %s slot", fieldName, param)
+	default:
+		return getValidationMessage(fieldName, tag, param)
+	}
+}
@@ -1,71 +1,581 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
 )
 
+const (
+	maxTopicsPerConnection = 50
+	fanoutChannel          = "duckparty:ws:notifications"
+
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 4096
+	sendBufferSize = 64
+)
+
+// fanoutMessage is what replicas exchange over Redis. Origin identifies the
+// publishing replica, which has already delivered the message locally.
+type fanoutMessage struct {
+	Origin  string          `json:"origin"`
+	Topics  []string        `json:"topics,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+var (
+	ErrTooManyConnections = errors.New("too many connections for this user")
+	errUserTopicForbidden = errors.New("user topics are only available for your own authenticated user")
+)
+
+// Authenticator resolves a client-supplied token to a user ID.
+type Authenticator func(token string) (uint, error)
+
+type BroadcasterOptions struct {
+	// Authenticate is used for tokens sent in an "auth" message. Without it,
+	// connections can only be authenticated when they are added.
+	Authenticate Authenticator
+	// MaxConnectionsPerUser caps concurrent authenticated connections per
+	// user on this replica. Connections are not counted across replicas, so
+	// with N replicas a user can hold up to N times this many. Zero means no
+	// limit.
+	MaxConnectionsPerUser int
+	// HistorySize is how many recent notifications are kept for clients
+	// resuming after a disconnect. Zero means DefaultHistorySize.
+	HistorySize int
+}
+
+// defaultTopics are subscribed on connect so clients that never send a
+// subscribe message keep receiving the party-wide feed.
+var defaultTopics = []string{TopicDucks}
+
+// subscriber owns one connection. Only its write pump writes to conn, so
+// gorilla's single-writer rule holds no matter who broadcasts.
+type subscriber struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	topics    map[string]struct{}
+	userID    uint
+}
+
+// enqueue reports false when the subscriber's queue is full.
+func (s *subscriber) enqueue(data []byte) bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+	}
+
+	select {
+	case s.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// enqueueWait blocks until data is queued, the subscriber closes or the
+// write deadline passes.
+func (s *subscriber) enqueueWait(data []byte) bool {
+	timer := time.NewTimer(writeWait)
+	defer timer.Stop()
+
+	select {
+	case s.send <- data:
+		return true
+	case <-s.done:
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+// Stats is a point-in-time view of the broadcaster on this instance.
+// Evictions counts connections closed for not keeping up with their
+// messages since the instance started.
+type Stats struct {
+	Connections int    `json:"connections" example:"42"`
+	Evictions   uint64 `json:"evictions" example:"3"`
+} // @name WebSocketStats
+
 type SocketBroadcaster struct {
-	mu    sync.RWMutex
-	conns []*websocket.Conn
+	mu          sync.RWMutex
+	subscribers map[*websocket.Conn]*subscriber
+	userConns   map[uint]int
+	rdb         *redis.Client
+	history     history
+	options     BroadcasterOptions
+	originID    string
+	evicted     atomic.Uint64
 }
 
-func NewSocketBroadcaster() *SocketBroadcaster {
+// NewSocketBroadcaster creates a broadcaster. With a Redis client, broadcasts
+// are also published to every other replica once Start has been called.
+func NewSocketBroadcaster(rdb *redis.Client, options BroadcasterOptions) *SocketBroadcaster {
 	return &SocketBroadcaster{
-		conns: make([]*websocket.Conn, 0),
+		subscribers: make(map[*websocket.Conn]*subscriber),
+		userConns:   make(map[uint]int),
+		rdb:         rdb,
+		history:     newHistory(rdb, options.HistorySize),
+		options:     options,
+		originID:    uuid.NewString(),
 	}
 }
 
-func (b *SocketBroadcaster) Add(conn *websocket.Conn) {
-	b.mu.Lock()
-	b.conns = append(b.conns, conn)
-	b.mu.Unlock()
+// Start relays broadcasts published by other replicas to local connections
+// until ctx is done.
+func (b *SocketBroadcaster) Start(ctx context.Context) {
+	if b.rdb == nil {
+		return
+	}
+
+	pubsub := b.rdb.Subscribe(ctx, fanoutChannel)
 
 	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
 		for {
-			_, _, err := conn.ReadMessage()
-			if err != nil {
-				b.Remove(conn)
+			select {
+			case <-ctx.Done():
 				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var message fanoutMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+					slog.Default().Error("failed to decode fan-out message", "error", err)
+					continue
+				}
+
+				if message.Origin == b.originID {
+					continue
+				}
+
+				b.deliver(message.Topics, message.Payload)
 			}
 		}
 	}()
 }
 
+// CanAccept reports whether userID may open another connection. Anonymous
+// connections (userID 0) are always accepted.
+func (b *SocketBroadcaster) CanAccept(userID uint) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.canAcceptLocked(userID)
+}
+
+func (b *SocketBroadcaster) canAcceptLocked(userID uint) bool {
+	if userID == 0 || b.options.MaxConnectionsPerUser <= 0 {
+		return true
+	}
+
+	return b.userConns[userID] < b.options.MaxConnectionsPerUser
+}
+
+// Add starts serving conn. userID is 0 for connections that have not
+// authenticated yet.
+func (b *SocketBroadcaster) Add(conn *websocket.Conn, userID uint) error {
+	sub := &subscriber{
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+		done:   make(chan struct{}),
+		topics: make(map[string]struct{}, len(defaultTopics)+1),
+	}
+	for _, topic := range defaultTopics {
+		sub.topics[topic] = struct{}{}
+	}
+
+	b.mu.Lock()
+	if !b.canAcceptLocked(userID) {
+		b.mu.Unlock()
+		conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ErrTooManyConnections.Error()),
+			time.Now().Add(writeWait),
+		)
+		conn.Close()
+		return ErrTooManyConnections
+	}
+	b.subscribers[conn] = sub
+	b.setUserLocked(sub, userID)
+	b.mu.Unlock()
+
+	go b.writePump(sub)
+	go b.readPump(sub)
+
+	return nil
+}
+
 func (b *SocketBroadcaster) Remove(conn *websocket.Conn) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	sub, ok := b.subscribers[conn]
+	if ok {
+		delete(b.subscribers, conn)
+		b.setUserLocked(sub, 0)
+	}
+	b.mu.Unlock()
 
-	for i, c := range b.conns {
-		if c == conn {
-			b.conns = append(b.conns[:i], b.conns[i+1:]...)
-			break
+	if ok {
+		sub.close()
+		return
+	}
+
+	conn.Close()
+}
+
+// setUserLocked moves sub to userID, keeping per-user counts and the
+// private user topic in sync. It must be called with the lock held.
+func (b *SocketBroadcaster) setUserLocked(sub *subscriber, userID uint) {
+	if sub.userID != 0 {
+		b.userConns[sub.userID]--
+		if b.userConns[sub.userID] <= 0 {
+			delete(b.userConns, sub.userID)
 		}
+		delete(sub.topics, UserTopic(sub.userID))
+	}
+
+	sub.userID = userID
+
+	if userID != 0 {
+		b.userConns[userID]++
+		sub.topics[UserTopic(userID)] = struct{}{}
 	}
 }
 
-func (b *SocketBroadcaster) Broadcast(message interface{}) error {
+func (b *SocketBroadcaster) Stats() Stats {
 	b.mu.RLock()
-	conns := make([]*websocket.Conn, len(b.conns))
-	copy(conns, b.conns)
+	connections := len(b.subscribers)
 	b.mu.RUnlock()
 
-	if len(conns) == 0 {
-		return nil
+	return Stats{
+		Connections: connections,
+		Evictions:   b.evicted.Load(),
+	}
+}
+
+func (b *SocketBroadcaster) readPump(sub *subscriber) {
+	defer b.Remove(sub.conn)
+
+	sub.conn.SetReadLimit(maxMessageSize)
+	sub.conn.SetReadDeadline(time.Now().Add(pongWait))
+	sub.conn.SetPongHandler(func(string) error {
+		return sub.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := sub.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		b.handleClientMessage(sub, data)
 	}
+}
+
+func (b *SocketBroadcaster) writePump(sub *subscriber) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		b.Remove(sub.conn)
+	}()
+
+	for {
+		select {
+		case <-sub.done:
+			return
+		case data := <-sub.send:
+			sub.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := sub.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			sub.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := sub.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send queues data for sub and evicts it if it cannot keep up.
+func (b *SocketBroadcaster) send(sub *subscriber, data []byte) {
+	if sub.enqueue(data) {
+		return
+	}
+
+	b.evicted.Add(1)
+	slog.Default().Warn("evicting slow WebSocket consumer", "remote_addr", sub.conn.RemoteAddr().String())
+
+	b.Remove(sub.conn)
+}
+
+// Broadcast stamps message with the next sequence ID, records it for replay
+// and delivers it to every subscribed connection on every replica.
+func (b *SocketBroadcaster) Broadcast(message *Notification) error {
+	ctx := context.Background()
+
+	id, err := b.history.NextID(ctx)
+	if err != nil {
+		slog.Default().Error("failed to assign notification sequence ID", "error", err)
+	}
+	message.ID = id
 
 	data, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
-	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			b.Remove(conn)
+	if message.ID != 0 {
+		event := historyEvent{ID: message.ID, Topics: message.Topics, Payload: data}
+		if err := b.history.Append(ctx, event); err != nil {
+			slog.Default().Error("failed to record notification for replay", "error", err)
 		}
 	}
 
+	b.deliver(message.Topics, data)
+
+	if b.rdb == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(fanoutMessage{
+		Origin:  b.originID,
+		Topics:  message.Topics,
+		Payload: data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := b.rdb.Publish(ctx, fanoutChannel, payload).Err(); err != nil {
+		slog.Default().Error("failed to publish fan-out message", "error", err)
+		return err
+	}
+
 	return nil
 }
+
+func (b *SocketBroadcaster) deliver(topics []string, data []byte) {
+	b.mu.RLock()
+	subscribers := make([]*subscriber, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		if sub.matches(topics) {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		b.send(sub, data)
+	}
+}
+
+func (b *SocketBroadcaster) handleClientMessage(sub *subscriber, data []byte) {
+	var message ClientMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		b.reply(sub, NewNotification(NotificationTypeError, ErrorData{Message: "invalid message"}))
+		return
+	}
+
+	for _, topic := range message.Topics {
+		if !IsValidTopic(topic) {
+			b.reply(sub, NewNotification(NotificationTypeError, ErrorData{Message: fmt.Sprintf("invalid topic %q", topic)}))
+			return
+		}
+	}
+
+	switch message.Action {
+	case ActionAuth:
+		userID, err := b.authenticate(sub, message.Token)
+		if err != nil {
+			b.reply(sub, NewNotification(NotificationTypeError, ErrorData{Message: err.Error()}))
+			return
+		}
+		b.reply(sub, NewNotification(NotificationTypeAuthenticated, AuthenticatedData{UserID: userID}))
+	case ActionSubscribe:
+		if err := b.subscribe(sub, message.Topics); err != nil {
+			b.reply(sub, NewNotification(NotificationTypeError, ErrorData{Message: err.Error()}))
+			return
+		}
+	case ActionUnsubscribe:
+		b.unsubscribe(sub, message.Topics)
+	case ActionResume:
+		b.resume(sub, message.LastID)
+		return
+	default:
+		b.reply(sub, NewNotification(NotificationTypeError, ErrorData{Message: fmt.Sprintf("unknown action %q", message.Action)}))
+		return
+	}
+
+	b.reply(sub, NewNotification(NotificationTypeSubscriptions, SubscriptionsData{Topics: b.topicsOf(sub)}))
+}
+
+func (b *SocketBroadcaster) authenticate(sub *subscriber, token string) (uint, error) {
+	if b.options.Authenticate == nil {
+		return 0, errors.New("authentication is not available")
+	}
+
+	userID, err := b.options.Authenticate(token)
+	if err != nil || userID == 0 {
+		return 0, errors.New("invalid token")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub.userID == userID {
+		return userID, nil
+	}
+
+	if !b.canAcceptLocked(userID) {
+		return 0, ErrTooManyConnections
+	}
+
+	b.setUserLocked(sub, userID)
+
+	return userID, nil
+}
+
+func (b *SocketBroadcaster) subscribe(sub *subscriber, topics []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		if strings.HasPrefix(topic, userTopicPrefix) && (sub.userID == 0 || topic != UserTopic(sub.userID)) {
+			return errUserTopicForbidden
+		}
+	}
+
+	added := 0
+	for _, topic := range topics {
+		if _, ok := sub.topics[topic]; !ok {
+			added++
+		}
+	}
+
+	if len(sub.topics)+added > maxTopicsPerConnection {
+		return fmt.Errorf("at most %d topics can be subscribed", maxTopicsPerConnection)
+	}
+
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+
+	return nil
+}
+
+func (b *SocketBroadcaster) unsubscribe(sub *subscriber, topics []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(sub.topics, topic)
+	}
+}
+
+func (b *SocketBroadcaster) topicsOf(sub *subscriber) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	topics := make([]string, 0, len(sub.topics))
+	for topic := range sub.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	return topics
+}
+
+// resume replays the notifications sub missed after lastID, limited to its
+// current subscriptions, or asks it to resync when they are no longer
+// retained. It runs on the read pump, so it can wait for queue space
+// instead of evicting the client for catching up.
+func (b *SocketBroadcaster) resume(sub *subscriber, lastID uint64) {
+	events, latestID, ok, err := b.history.Since(context.Background(), lastID)
+	if err != nil {
+		slog.Default().Error("failed to load notification history", "error", err)
+	}
+	if err != nil || !ok {
+		b.replyWait(sub, NewNotification(NotificationTypeResyncRequired, ResyncRequiredData{LastID: latestID}))
+		return
+	}
+
+	b.mu.RLock()
+	missed := make([]historyEvent, 0, len(events))
+	for _, event := range events {
+		if sub.matches(event.Topics) {
+			missed = append(missed, event)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, event := range missed {
+		if !sub.enqueueWait(event.Payload) {
+			return
+		}
+	}
+
+	b.replyWait(sub, NewNotification(NotificationTypeResumed, ResumedData{LastID: latestID, Replayed: len(missed)}))
+}
+
+func (b *SocketBroadcaster) reply(sub *subscriber, message *Notification) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	b.send(sub, data)
+}
+
+// replyWait is reply for the read pump: it waits for queue space, so a
+// replay that just filled the queue does not get the client evicted.
+func (b *SocketBroadcaster) replyWait(sub *subscriber, message *Notification) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	sub.enqueueWait(data)
+}
+
+// matches must be called with the broadcaster lock held.
+func (s *subscriber) matches(topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+
+	for _, topic := range topics {
+		if _, ok := s.topics[topic]; ok {
+			return true
+		}
+	}
+
+	return false
+}
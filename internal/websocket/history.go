@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultHistorySize = 1000
+
+	historySequenceKey = "duckparty:ws:seq"
+	historyEventsKey   = "duckparty:ws:history"
+)
+
+// historyEvent is a broadcast notification as it is retained for replay.
+// Payload is the encoded Notification, sequence ID included.
+type historyEvent struct {
+	ID      uint64          `json:"id"`
+	Topics  []string        `json:"topics,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// history assigns sequence IDs to notifications and keeps the most recent
+// ones so reconnecting clients can catch up.
+type history interface {
+	NextID(ctx context.Context) (uint64, error)
+	Append(ctx context.Context, event historyEvent) error
+	// Since returns the events after lastID in order. ok is false when
+	// events after lastID have already been trimmed and the client has to
+	// resync instead.
+	Since(ctx context.Context, lastID uint64) (events []historyEvent, latestID uint64, ok bool, err error)
+}
+
+func newHistory(rdb *redis.Client, size int) history {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+
+	if rdb != nil {
+		return &redisHistory{rdb: rdb, size: int64(size)}
+	}
+
+	return &memoryHistory{events: make([]historyEvent, 0, size), size: size}
+}
+
+// redisHistory shares sequence IDs and retained events between replicas. The
+// events live in a sorted set scored by ID and trimmed to size.
+type redisHistory struct {
+	rdb  *redis.Client
+	size int64
+}
+
+func (h *redisHistory) NextID(ctx context.Context) (uint64, error) {
+	id, err := h.rdb.Incr(ctx, historySequenceKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(id), nil
+}
+
+func (h *redisHistory) Append(ctx context.Context, event historyEvent) error {
+	member, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, historyEventsKey, redis.Z{Score: float64(event.ID), Member: member})
+		pipe.ZRemRangeByRank(ctx, historyEventsKey, 0, -h.size-1)
+		return nil
+	})
+
+	return err
+}
+
+func (h *redisHistory) Since(ctx context.Context, lastID uint64) ([]historyEvent, uint64, bool, error) {
+	latest, err := h.rdb.Get(ctx, historySequenceKey).Uint64()
+	if err != nil && err != redis.Nil {
+		return nil, 0, false, err
+	}
+
+	if lastID >= latest {
+		return nil, latest, lastID == latest, nil
+	}
+
+	oldest, err := h.rdb.ZRangeWithScores(ctx, historyEventsKey, 0, 0).Result()
+	if err != nil {
+		return nil, latest, false, err
+	}
+	if len(oldest) == 0 || uint64(oldest[0].Score) > lastID+1 {
+		return nil, latest, false, nil
+	}
+
+	members, err := h.rdb.ZRangeByScore(ctx, historyEventsKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatUint(lastID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, latest, false, err
+	}
+
+	events := make([]historyEvent, 0, len(members))
+	for _, member := range members {
+		var event historyEvent
+		if err := json.Unmarshal([]byte(member), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, latest, true, nil
+}
+
+// memoryHistory is a ring buffer for single-replica setups without Redis.
+type memoryHistory struct {
+	mu     sync.RWMutex
+	lastID uint64
+	events []historyEvent
+	start  int
+	size   int
+}
+
+func (h *memoryHistory) NextID(ctx context.Context) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	return h.lastID, nil
+}
+
+func (h *memoryHistory) Append(ctx context.Context, event historyEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.events) < h.size {
+		h.events = append(h.events, event)
+		return nil
+	}
+
+	h.events[h.start] = event
+	h.start = (h.start + 1) % h.size
+
+	return nil
+}
+
+func (h *memoryHistory) Since(ctx context.Context, lastID uint64) ([]historyEvent, uint64, bool, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if lastID >= h.lastID {
+		return nil, h.lastID, lastID == h.lastID, nil
+	}
+
+	if len(h.events) == 0 || h.events[h.start].ID > lastID+1 {
+		return nil, h.lastID, false, nil
+	}
+
+	events := make([]historyEvent, 0, h.lastID-lastID)
+	for i := range h.events {
+		event := h.events[(h.start+i)%len(h.events)]
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+
+	return events, h.lastID, true, nil
+}
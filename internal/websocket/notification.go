@@ -1,17 +1,78 @@
 package websocket
 
 const (
-	NotificationTypeNewDuck = "new_duck_created"
+	NotificationTypeNewDuck             = "new_duck_created"
+	NotificationTypeDuckReactionChanged = "duck_reaction_changed"
+	NotificationTypeReactionReceived    = "duck_reaction_received"
+	NotificationTypeDuckRemoved         = "duck_removed"
+	NotificationTypeDuckRestored        = "duck_restored"
+	NotificationTypeDuckRenamed         = "duck_renamed"
+	NotificationTypeDuckMoved           = "duck_moved"
+	NotificationTypeDuckUpdated         = "duck_updated"
+	NotificationTypeLeaderboardUpdated  = "leaderboard_updated"
+	NotificationTypeSubscriptions       = "subscriptions"
+	NotificationTypeAuthenticated       = "authenticated"
+	NotificationTypeResumed             = "resumed"
+	NotificationTypeResyncRequired      = "resync_required"
+	NotificationTypeError               = "error"
 )
 
+// Notification is delivered to connections subscribed to any of its topics.
+// A notification without topics goes to every connection. Broadcast
+// notifications carry an increasing ID that clients pass back to resume;
+// direct replies have none.
 type Notification struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	ID     uint64      `json:"id,omitempty"`
+	Type   string      `json:"type"`
+	Topics []string    `json:"topics,omitempty"`
+	Data   interface{} `json:"data"`
 }
 
-func NewNotification(notificationType string, data interface{}) *Notification {
+type DuckReactionChangedData struct {
+	DuckID         uint             `json:"duck_id"`
+	ReactionCounts map[string]int64 `json:"reaction_counts"`
+}
+
+// ReactionReceivedData is sent privately to a duck's owner when someone
+// else reacts to it.
+type ReactionReceivedData struct {
+	DuckID    uint   `json:"duck_id"`
+	ReactorID uint   `json:"reactor_id"`
+	Reaction  string `json:"reaction"`
+}
+
+type DuckRemovedData struct {
+	DuckID  uint `json:"duck_id"`
+	OwnerID uint `json:"owner_id"`
+}
+
+type DuckRenamedData struct {
+	DuckID uint   `json:"duck_id"`
+	Name   string `json:"name"`
+}
+
+type DuckMovedData struct {
+	DuckID uint    `json:"duck_id"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+}
+
+// RankChange describes a duck whose leaderboard rank moved. A rank of 0 means
+// the duck was unranked.
+type RankChange struct {
+	DuckID       uint `json:"duck_id"`
+	PreviousRank uint `json:"previous_rank"`
+	Rank         uint `json:"rank"`
+}
+
+type LeaderboardUpdatedData struct {
+	Changes []RankChange `json:"changes"`
+}
+
+func NewNotification(notificationType string, data interface{}, topics ...string) *Notification {
 	return &Notification{
-		Type: notificationType,
-		Data: data,
+		Type:   notificationType,
+		Topics: topics,
+		Data:   data,
 	}
 }
@@ -0,0 +1,43 @@
+package websocket
+
+const (
+	ActionAuth        = "auth"
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+	ActionResume      = "resume"
+)
+
+// ClientMessage is what clients send over the socket, e.g.
+// {"action":"subscribe","topics":["duck:42","leaderboard"]} or
+// {"action":"auth","token":"<jwt>"} or {"action":"resume","last_id":1234}.
+type ClientMessage struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics,omitempty"`
+	Token  string   `json:"token,omitempty"`
+	LastID uint64   `json:"last_id,omitempty"`
+}
+
+type SubscriptionsData struct {
+	Topics []string `json:"topics"`
+}
+
+type ErrorData struct {
+	Message string `json:"message"`
+}
+
+type AuthenticatedData struct {
+	UserID uint `json:"user_id"`
+}
+
+// ResumedData follows the replayed notifications. LastID is the latest
+// sequence ID at the time of the replay.
+type ResumedData struct {
+	LastID   uint64 `json:"last_id"`
+	Replayed int    `json:"replayed"`
+}
+
+// ResyncRequiredData tells a resuming client that the notifications it
+// missed are no longer retained and it has to reload its state.
+type ResyncRequiredData struct {
+	LastID uint64 `json:"last_id"`
+}
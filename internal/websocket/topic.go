@@ -0,0 +1,39 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	TopicDucks       = "ducks"
+	TopicLeaderboard = "leaderboard"
+
+	duckTopicPrefix = "duck:"
+	userTopicPrefix = "user:"
+)
+
+func DuckTopic(duckID uint) string {
+	return fmt.Sprintf("%s%d", duckTopicPrefix, duckID)
+}
+
+func UserTopic(userID uint) string {
+	return fmt.Sprintf("%s%d", userTopicPrefix, userID)
+}
+
+func IsValidTopic(topic string) bool {
+	switch topic {
+	case TopicDucks, TopicLeaderboard:
+		return true
+	}
+
+	for _, prefix := range []string{duckTopicPrefix, userTopicPrefix} {
+		if id, ok := strings.CutPrefix(topic, prefix); ok {
+			parsed, err := strconv.ParseUint(id, 10, 64)
+			return err == nil && parsed > 0
+		}
+	}
+
+	return false
+}
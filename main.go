@@ -15,6 +15,11 @@ import "github.com/omidnikrah/duckparty-backend/cmd/server"
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey AdminKey
+// @in header
+// @name X-Admin-Key
+// @description Shared admin key configured through ADMIN_API_KEY.
+
 func main() {
 	server.Setup()
 }